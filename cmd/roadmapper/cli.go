@@ -8,24 +8,162 @@ import (
 	"github.com/peteraba/roadmapper/pkg/roadmap"
 )
 
+// RenderOptions holds the settings used for rendering a roadmap, empty values fall back to defaults
+type RenderOptions struct {
+	InputFormat      string
+	FileFormat       string
+	DateFormat       string
+	BaseURL          string
+	Width            uint64
+	LineHeight       uint64
+	MarkToday        bool
+	ReferenceDate    string
+	Layout           string
+	Include          string
+	Exclude          string
+	TitleTemplate    string
+	Timezone         string
+	CalendarProjects bool
+}
+
 // Render renders a roadmap
-func Render(io roadmap.IO, l *zap.Logger, content, output string, fileFormat, dateFormat, baseUrl string, fw, lh uint64, mt bool) error {
-	format, err := roadmap.NewFormatType(fileFormat)
+func Render(io roadmap.IO, l *zap.Logger, content, output string, opts RenderOptions) error {
+	inFormat, err := roadmap.NewInputFormat(opts.InputFormat)
+	if err != nil {
+		l.Info("input format is not supported", zap.Error(err))
+
+		return err
+	}
+
+	format, err := roadmap.NewFormatType(opts.FileFormat)
 	if err != nil {
 		l.Info("format is not supported", zap.Error(err))
 
 		return err
 	}
 
-	fw, lh = roadmap.GetCanvasSizes(fw, lh)
+	layout, err := roadmap.NewLayout(opts.Layout)
+	if err != nil {
+		l.Info("layout is not supported", zap.Error(err))
+
+		return err
+	}
+
+	tz, err := roadmap.ParseTimezone(opts.Timezone)
+	if err != nil {
+		l.Info("timezone is not supported", zap.Error(err))
 
-	r := roadmap.Content(content).ToRoadmap(0, nil, "", dateFormat, baseUrl, time.Now())
+		return err
+	}
 
-	cvs := r.ToVisual().Draw(float64(fw), float64(lh), mt)
+	fw, lh := roadmap.GetCanvasSizes(opts.Width, opts.LineHeight)
 
-	img := roadmap.RenderImg(cvs, format)
+	r, err := readRoadmap(l, content, inFormat, opts.DateFormat, opts.BaseURL)
+	if err != nil {
+		l.Info("roadmap is not valid", zap.Error(err))
+
+		return err
+	}
+
+	referenceAt, err := roadmap.ParseReferenceDate(opts.ReferenceDate, r.DateFormat)
+	if err != nil {
+		l.Info("reference date is not valid", zap.Error(err))
+
+		return err
+	}
+
+	include, exclude := roadmap.ParseLabelList(opts.Include), roadmap.ParseLabelList(opts.Exclude)
+
+	vr := r.ToVisual().FilterByLabels(include, exclude).ApplyLayout(layout).ApplyTitleTemplate(opts.TitleTemplate).ApplyTimezone(tz).ApplyCalendarProjects(opts.CalendarProjects)
+
+	img := vr.Render(format, float64(fw), float64(lh), opts.MarkToday || opts.ReferenceDate != "", referenceAt)
 
 	err = io.Write(output, string(img))
 
 	return err
 }
+
+// Convert converts a roadmap from one format into another (e.g. from txt to csv)
+func Convert(io roadmap.IO, l *zap.Logger, content, output, inputFormat, outputFormat, dateFormat, baseUrl string) error {
+	inFormat, err := roadmap.NewInputFormat(inputFormat)
+	if err != nil {
+		l.Info("input format is not supported", zap.Error(err))
+
+		return err
+	}
+
+	outFormat, err := roadmap.NewInputFormat(outputFormat)
+	if err != nil {
+		l.Info("output format is not supported", zap.Error(err))
+
+		return err
+	}
+
+	r, err := readRoadmap(l, content, inFormat, dateFormat, baseUrl)
+	if err != nil {
+		l.Info("roadmap is not valid", zap.Error(err))
+
+		return err
+	}
+
+	data, err := r.Encode(outFormat)
+	if err != nil {
+		l.Info("roadmap could not be converted", zap.Error(err))
+
+		return err
+	}
+
+	return io.Write(output, string(data))
+}
+
+// readRoadmap converts the content read into a Roadmap based on the input format
+// problems found in the content are logged, but don't stop rendering
+func readRoadmap(l *zap.Logger, content string, inFormat roadmap.InputFormat, dateFormat, baseUrl string) (roadmap.Roadmap, error) {
+	var (
+		r   roadmap.Roadmap
+		ds  roadmap.Diagnostics
+		err error
+	)
+
+	switch inFormat {
+	case roadmap.TextFormat:
+		r, ds = roadmap.Content(content).Parse(0, nil, "", dateFormat, baseUrl, time.Now())
+	case roadmap.MermaidInputFormat:
+		r, ds = roadmap.ParseMermaid(content, 0, nil, "", baseUrl, time.Now())
+	case roadmap.CSVFormat:
+		r, ds = roadmap.ParseCSV(content, 0, nil, "", dateFormat, baseUrl, time.Now())
+	default:
+		r, err = roadmap.ParseExchange([]byte(content), inFormat, dateFormat, baseUrl)
+		if err != nil {
+			return r, err
+		}
+
+		ds = r.Validate()
+	}
+
+	logDiagnostics(l, ds)
+
+	return r, nil
+}
+
+// logDiagnostics logs the problems found in a roadmap, so that they can be fixed
+func logDiagnostics(l *zap.Logger, ds roadmap.Diagnostics) {
+	for _, d := range ds {
+		fields := []zap.Field{
+			zap.Int("line", d.Line),
+			zap.Int("column", d.Column),
+			zap.String("token", d.Token),
+		}
+
+		if d.Path != "" {
+			fields = []zap.Field{zap.String("path", d.Path), zap.String("token", d.Token)}
+		}
+
+		if d.Severity == roadmap.SeverityError {
+			l.Error(d.Message, fields...)
+			continue
+		}
+
+		l.Warn(d.Message, fields...)
+	}
+}
@@ -70,18 +70,14 @@ func TestE2E_CLI(t *testing.T) {
 
 			expectedData := testutils.LoadFile(t, "golden_files", tt.args.output)
 
-			err := Render(
-				rw,
-				logger,
-				tt.args.content,
-				tt.args.output,
-				tt.args.format,
-				tt.args.dateFormat,
-				tt.args.baseUrl,
-				tt.args.fw,
-				tt.args.lh,
-				tt.args.mt,
-			)
+			err := Render(rw, logger, tt.args.content, tt.args.output, RenderOptions{
+				FileFormat: tt.args.format,
+				DateFormat: tt.args.dateFormat,
+				BaseURL:    tt.args.baseUrl,
+				Width:      tt.args.fw,
+				LineHeight: tt.args.lh,
+				MarkToday:  tt.args.mt,
+			})
 
 			require.NoError(t, err)
 
@@ -55,7 +55,7 @@ func TestIntegration_TextToRoadmap(t *testing.T) {
 
 	actual := roadmap.String()
 
-	assert.Equal(t, txt, actual)
+	assert.Equal(t, "---\nbaseUrl: "+txtBaseURL+"\n---\n"+txt, actual)
 }
 
 func TestIntegration_TextToVisual(t *testing.T) {
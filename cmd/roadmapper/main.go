@@ -42,6 +42,7 @@ func createApp(logger *zap.Logger, codeBuilder code.Builder) *cli.App {
 		Commands: []*cli.Command{
 			createServerCommand(logger, codeBuilder),
 			createCLICommand(logger),
+			createConvertCommand(logger),
 			createVersionCommand(),
 			createMigrateDownCommand(logger),
 			createMigrateUpCommand(logger),
@@ -102,12 +103,20 @@ func createCLICommand(logger *zap.Logger) *cli.Command {
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "input", Usage: "input file", Aliases: []string{"i"}},
 			&cli.StringFlag{Name: "output", Usage: "output file", Aliases: []string{"o"}},
-			&cli.StringFlag{Name: "formatFile", Usage: "image format to be used (supported: svg, png)", Aliases: []string{"f"}, Value: "svg", EnvVars: []string{"IMAGE_FORMAT"}},
+			&cli.StringFlag{Name: "inputFormat", Usage: "format of the input (supported: txt, json, yaml, mermaid, csv), detected from the extension of the input file if empty", Value: "", EnvVars: []string{"INPUT_FORMAT"}},
+			&cli.StringFlag{Name: "formatFile", Usage: "output format to be used (supported: svg, png, mermaid, ics)", Aliases: []string{"f"}, Value: "svg", EnvVars: []string{"IMAGE_FORMAT"}},
 			&cli.Uint64Flag{Name: "width", Usage: "width of output file", Aliases: []string{"w"}},
 			&cli.Uint64Flag{Name: "lineHeight", Usage: "width of output file", Aliases: []string{"lh"}},
-			&cli.StringFlag{Name: "dateFormat", Usage: "date format to use", Value: "2006-01-02", EnvVars: []string{"DATE_FORMAT"}},
+			&cli.StringFlag{Name: "dateFormat", Usage: "date format to use, detected from the dates of the input if empty", Value: "", EnvVars: []string{"DATE_FORMAT"}},
 			&cli.StringFlag{Name: "baseURL", Usage: "base url to use for non-color, non-date extra values", Value: "", EnvVars: []string{"BASE_URL"}},
-			&cli.StringFlag{Name: "markToday", Usage: "weather or not to add a line to mark the current day", Value: "", EnvVars: []string{"MARK_TODAY"}},
+			&cli.BoolFlag{Name: "markToday", Usage: "whether or not to add a line to mark the current day", EnvVars: []string{"MARK_TODAY"}},
+			&cli.StringFlag{Name: "referenceDate", Usage: "date to mark instead of the current day, e.g. 2020-03-01, implies markToday", Value: "", EnvVars: []string{"REFERENCE_DATE"}},
+			&cli.StringFlag{Name: "layout", Usage: "layout to use (supported: default, swimlanes)", Value: "default", EnvVars: []string{"LAYOUT"}},
+			&cli.StringFlag{Name: "include", Usage: "comma separated list of labels, only projects having any of them are rendered", Value: ""},
+			&cli.StringFlag{Name: "exclude", Usage: "comma separated list of labels, projects having any of them are not rendered", Value: ""},
+			&cli.StringFlag{Name: "titleTemplate", Usage: "template for displaying titles, e.g. \"{title} ({team})\" uses the team field of projects", Value: ""},
+			&cli.StringFlag{Name: "timezone", Usage: "timezone used for marking the current day, e.g. Europe/Berlin, overwrites the timezone of the roadmap", Value: "", EnvVars: []string{"TIMEZONE"}},
+			&cli.BoolFlag{Name: "calendarProjects", Usage: "whether or not to add projects as events to ics output, only milestones are added by default", EnvVars: []string{"CALENDAR_PROJECTS"}},
 		},
 		Action: func(c *cli.Context) error {
 			content, err := readContent(c.String("input"))
@@ -116,21 +125,79 @@ func createCLICommand(logger *zap.Logger) *cli.Command {
 				return err
 			}
 
+			inputFormat := c.String("inputFormat")
+			if inputFormat == "" {
+				inputFormat = string(roadmap.InputFormatByExtension(c.String("input")))
+			}
+
 			io := roadmap.NewIO()
-			err = Render(
+			err = Render(io, logger, content, c.String("output"), RenderOptions{
+				InputFormat:      inputFormat,
+				FileFormat:       c.String("formatFile"),
+				DateFormat:       c.String("dateFormat"),
+				BaseURL:          c.String("baseURL"),
+				Width:            c.Uint64("width"),
+				LineHeight:       c.Uint64("lineHeight"),
+				MarkToday:        c.Bool("markToday"),
+				ReferenceDate:    c.String("referenceDate"),
+				Layout:           c.String("layout"),
+				Include:          c.String("include"),
+				Exclude:          c.String("exclude"),
+				TitleTemplate:    c.String("titleTemplate"),
+				Timezone:         c.String("timezone"),
+				CalendarProjects: c.Bool("calendarProjects"),
+			})
+			if err != nil {
+				logger.Error("failed to render roadmap", zap.Error(err))
+			}
+
+			return err
+		},
+	}
+}
+
+func createConvertCommand(logger *zap.Logger) *cli.Command {
+	return &cli.Command{
+		Name:  "convert",
+		Usage: "converts a roadmap into another format",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "input", Usage: "input file", Aliases: []string{"i"}},
+			&cli.StringFlag{Name: "output", Usage: "output file", Aliases: []string{"o"}},
+			&cli.StringFlag{Name: "inputFormat", Usage: "format of the input (supported: txt, json, yaml, mermaid, csv), detected from the extension of the input file if empty", Value: "", EnvVars: []string{"INPUT_FORMAT"}},
+			&cli.StringFlag{Name: "outputFormat", Usage: "format of the output (supported: txt, json, yaml, mermaid, csv), detected from the extension of the output file if empty", Aliases: []string{"f"}, Value: "", EnvVars: []string{"OUTPUT_FORMAT"}},
+			&cli.StringFlag{Name: "dateFormat", Usage: "date format to use, detected from the dates of the input if empty", Value: "", EnvVars: []string{"DATE_FORMAT"}},
+			&cli.StringFlag{Name: "baseURL", Usage: "base url to use for non-color, non-date extra values", Value: "", EnvVars: []string{"BASE_URL"}},
+		},
+		Action: func(c *cli.Context) error {
+			content, err := readContent(c.String("input"))
+			if err != nil {
+				logger.Error("failed to convert roadmap", zap.Error(err))
+				return err
+			}
+
+			inputFormat := c.String("inputFormat")
+			if inputFormat == "" {
+				inputFormat = string(roadmap.InputFormatByExtension(c.String("input")))
+			}
+
+			outputFormat := c.String("outputFormat")
+			if outputFormat == "" {
+				outputFormat = string(roadmap.InputFormatByExtension(c.String("output")))
+			}
+
+			io := roadmap.NewIO()
+			err = Convert(
 				io,
 				logger,
 				content,
 				c.String("output"),
-				c.String("formatFile"),
+				inputFormat,
+				outputFormat,
 				c.String("dateFormat"),
 				c.String("baseURL"),
-				c.Uint64("width"),
-				c.Uint64("lineHeight"),
-				c.Bool("markToday"),
 			)
 			if err != nil {
-				logger.Error("failed to render roadmap", zap.Error(err))
+				logger.Error("failed to convert roadmap", zap.Error(err))
 			}
 
 			return err
@@ -38,7 +38,7 @@ func Test_createApp(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got := createApp(tt.args.logger, tt.args.b)
 
-			assert.Len(t, got.Commands, 5)
+			assert.Len(t, got.Commands, 6)
 		})
 	}
 }
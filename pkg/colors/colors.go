@@ -254,10 +254,54 @@ var colors = [][]color.RGBA{
 	},
 }
 
+// Theme represents a set of color palettes used for coloring projects
+type Theme string
+
+const (
+	DefaultTheme Theme = "default"
+	CoolTheme    Theme = "cool"
+	WarmTheme    Theme = "warm"
+	MonoTheme    Theme = "mono"
+)
+
+// themes maps themes to the indexes of the palettes they use
+var themes = map[Theme][]int{
+	DefaultTheme: {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	CoolTheme:    {0, 3, 4, 6, 8},
+	WarmTheme:    {1, 2, 5, 7},
+	MonoTheme:    {9},
+}
+
+var errUnknownTheme = errors.New("unknown theme, supported themes: default, cool, warm, mono")
+
+// NewTheme converts a string into a Theme, an empty string results in the default theme
+func NewTheme(name string) (Theme, error) {
+	if name == "" {
+		return DefaultTheme, nil
+	}
+
+	t := Theme(strings.ToLower(name))
+	if _, ok := themes[t]; !ok {
+		return DefaultTheme, errUnknownTheme
+	}
+
+	return t, nil
+}
+
+// palette returns the palette used for an epic, unknown themes use the palettes of the default theme
+func (t Theme) palette(epicCount int) []color.RGBA {
+	indexes, ok := themes[t]
+	if !ok {
+		indexes = themes[DefaultTheme]
+	}
+
+	return colors[indexes[epicCount%len(indexes)]]
+}
+
 // PickFgColor will pick a color for a project based on
 // the number of epic, task and indentation
-func PickFgColor(epicCount, taskCount, indentation int) *color.RGBA {
-	c := colors[epicCount%len(colors)]
+func (t Theme) PickFgColor(epicCount, taskCount, indentation int) *color.RGBA {
+	c := t.palette(epicCount)
 
 	switch indentation {
 	case 0:
@@ -280,12 +324,22 @@ func PickFgColor(epicCount, taskCount, indentation int) *color.RGBA {
 }
 
 // PickBgColor will pick a color for main project
-func PickBgColor(epicCount int) color.RGBA {
-	c := colors[epicCount%len(colors)]
+func (t Theme) PickBgColor(epicCount int) color.RGBA {
+	c := t.palette(epicCount)
 
 	return c[len(c)-1]
 }
 
+// PickFgColor will pick a color for a project using the default theme
+func PickFgColor(epicCount, taskCount, indentation int) *color.RGBA {
+	return DefaultTheme.PickFgColor(epicCount, taskCount, indentation)
+}
+
+// PickBgColor will pick a color for main project using the default theme
+func PickBgColor(epicCount int) color.RGBA {
+	return DefaultTheme.PickBgColor(epicCount)
+}
+
 // mustParseColor turns parses a string as a hexadecimal color
 // it will panic in case it is not possible
 // meant to be used for hardcoded colors...
@@ -122,6 +122,52 @@ func Test_PickBgColor(t *testing.T) {
 	}
 }
 
+func Test_NewTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string
+		want    Theme
+		wantErr bool
+	}{
+		{"empty", "", DefaultTheme, false},
+		{"known theme", "cool", CoolTheme, false},
+		{"case insensitive", "Mono", MonoTheme, false},
+		{"unknown theme", "neon", DefaultTheme, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTheme(tt.theme)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NewTheme() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTheme_PickBgColor(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme Theme
+		epic  int
+		want  color.RGBA
+	}{
+		{"default theme", DefaultTheme, 1, colors[1][len(colors[1])-1]},
+		{"cool theme", CoolTheme, 1, colors[3][len(colors[3])-1]},
+		{"mono theme", MonoTheme, 3, colors[9][len(colors[9])-1]},
+		{"unknown theme", Theme("neon"), 1, colors[1][len(colors[1])-1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.theme.PickBgColor(tt.epic); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Theme.PickBgColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_mustParseColor(t *testing.T) {
 	type args struct {
 		part string
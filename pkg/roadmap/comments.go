@@ -0,0 +1,124 @@
+package roadmap
+
+import (
+	"strings"
+)
+
+// commentPrefix starts a comment, either at the beginning of a line (full-line comment) or after a whitespace at the
+// end of a line (trailing comment)
+const commentPrefix = "//"
+
+// lineComments represents the comments found in a Content
+type lineComments struct {
+	// fullLine contains the full-line comments found, keyed by line index
+	fullLine map[int]string
+	// trailing contains the trailing comments found, keyed by line index
+	trailing map[int]string
+}
+
+// splitComment separates a line from its comment
+// the line returned is empty for full-line comments
+func splitComment(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if strings.HasPrefix(trimmed, commentPrefix) {
+		return "", strings.TrimSpace(trimmed[len(commentPrefix):]), true
+	}
+
+	for i := 1; i < len(line); i++ {
+		if line[i-1] != ' ' && line[i-1] != '\t' {
+			continue
+		}
+
+		if strings.HasPrefix(line[i:], commentPrefix) {
+			return strings.TrimRight(line[:i], " \t"), strings.TrimSpace(line[i+len(commentPrefix):]), true
+		}
+	}
+
+	return line, "", false
+}
+
+// extractComments removes all comments from a Content
+// lines of full-line comments are replaced by empty lines, so that line numbers don't change
+func (c Content) extractComments() (Content, lineComments) {
+	lc := lineComments{fullLine: map[int]string{}, trailing: map[int]string{}}
+
+	lines := c.ToLines()
+
+	for i, line := range lines {
+		rest, comment, found := splitComment(line)
+		if !found {
+			continue
+		}
+
+		lines[i] = rest
+
+		if rest == "" {
+			lc.fullLine[i] = comment
+		} else {
+			lc.trailing[i] = comment
+		}
+	}
+
+	return Content(strings.Join(lines, "\n")), lc
+}
+
+// attachComments stores comments on the projects and milestones of a roadmap
+// full-line comments belong to the project or milestone following them, full-line comments after the last project or
+// milestone are stored on the roadmap itself
+func (r *Roadmap) attachComments(c Content, lc lineComments) {
+	var (
+		pending      []string
+		projectIdx   int
+		milestoneIdx int
+	)
+
+	for i, line := range c.ToLines() {
+		if comment, ok := lc.fullLine[i]; ok {
+			pending = append(pending, comment)
+
+			continue
+		}
+
+		if isLineProject(line) && projectIdx < len(r.Projects) {
+			r.Projects[projectIdx].Comments = pending
+			r.Projects[projectIdx].Comment = lc.trailing[i]
+			pending = nil
+			projectIdx++
+
+			continue
+		}
+
+		if isLineMilestone(line) && milestoneIdx < len(r.Milestones) {
+			r.Milestones[milestoneIdx].Comments = pending
+			r.Milestones[milestoneIdx].Comment = lc.trailing[i]
+			pending = nil
+			milestoneIdx++
+		}
+	}
+
+	r.Comments = pending
+}
+
+// withComments adds the comments of a project or milestone to the line representing it
+func withComments(line, indentation string, comments []string, comment string) string {
+	var lines []string
+
+	for _, c := range comments {
+		lines = append(lines, indentation+commentLine(c))
+	}
+
+	if comment != "" {
+		line = line + " " + commentLine(comment)
+	}
+
+	return strings.Join(append(lines, line), "\n")
+}
+
+// commentLine converts the text of a comment into a comment
+func commentLine(comment string) string {
+	if comment == "" {
+		return commentPrefix
+	}
+
+	return commentPrefix + " " + comment
+}
@@ -0,0 +1,59 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitComment(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		want  string
+		want1 string
+		want2 bool
+	}{
+		{"no comment", "foo [https://example.com/foo]", "foo [https://example.com/foo]", "", false},
+		{"full-line comment", "\t\t// foo bar", "", "foo bar", true},
+		{"empty comment", "//", "", "", true},
+		{"trailing comment", "\tfoo [2020-02-12] // bar baz", "\tfoo [2020-02-12]", "bar baz", true},
+		{"slashes without whitespace are not comments", "foo//bar", "foo//bar", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, got2 := splitComment(tt.line)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+			assert.Equal(t, tt.want2, got2)
+		})
+	}
+}
+
+func TestContent_Parse_comments(t *testing.T) {
+	const content = `// Phase 1
+Bring website online
+	// Needs budget approval
+	// Ask finance
+	Select and purchase domain [2020-02-12, 2020-02-20] // owned by ops
+Create server infrastructure
+
+// Milestones
+|Milestone 0.1 [2020-03-01] // first release
+// Nothing after this`
+
+	r, ds := Content(content).Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Equal(t, []string{"Phase 1"}, r.Projects[0].Comments)
+	assert.Equal(t, []string{"Needs budget approval", "Ask finance"}, r.Projects[1].Comments)
+	assert.Equal(t, "owned by ops", r.Projects[1].Comment)
+	assert.Equal(t, "Select and purchase domain", r.Projects[1].Title)
+	assert.Empty(t, r.Projects[2].Comments)
+	assert.Equal(t, []string{"Milestones"}, r.Milestones[0].Comments)
+	assert.Equal(t, "first release", r.Milestones[0].Comment)
+	assert.Equal(t, []string{"Nothing after this"}, r.Comments)
+
+	assert.Equal(t, content, r.String())
+}
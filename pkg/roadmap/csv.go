@@ -0,0 +1,662 @@
+package roadmap
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peteraba/roadmapper/pkg/colors"
+)
+
+// CSV roadmaps consist of three sections, each starting with a header row: settings, projects and milestones
+// the sections are recognized by the name of their first column, the other columns may be reordered or left out
+// cells holding multiple values (e.g. urls) contain one value per line
+// projects and milestones of included roadmaps are exported for reference, but skipped when importing, see IncludedFrom
+// cells which spreadsheets would read as formulas (e.g. +2w, -3d, =x, @x) are prefixed with an apostrophe on export,
+// which is removed again on import, see csvEscapeCell
+// diagnostics of CSV roadmaps refer to rows and columns instead of lines and columns, both starting with 1
+const (
+	csvSettingColumn   = "setting"
+	csvValueColumn     = "value"
+	csvCommentSetting  = "comment"
+	csvHeaderComment   = "headerComment"
+	csvLevelColumn     = "level"
+	csvKeyColumn       = "key"
+	csvTitleColumn     = "title"
+	csvStartColumn     = "start"
+	csvEndColumn       = "end"
+	csvDeadlineColumn  = "deadline"
+	csvPercentageCol   = "percentage"
+	csvColorColumn     = "color"
+	csvURLsColumn      = "urls"
+	csvMilestoneColumn = "milestone"
+	csvPhasesColumn    = "phases"
+	csvWeightColumn    = "weight"
+	csvEstimateColumn  = "estimate"
+	csvStatusColumn    = "status"
+	csvOwnersColumn    = "owners"
+	csvLabelsColumn    = "labels"
+	csvFieldsColumn    = "fields"
+	csvIDColumn        = "id"
+	csvAfterColumn     = "after"
+	csvIncludeColumn   = "include"
+	csvIncludedFromCol = "included_from"
+	csvDescriptionCol  = "description"
+	csvCommentsColumn  = "comments"
+	csvCommentColumn   = "comment"
+)
+
+var (
+	csvSettingColumns   = []string{csvSettingColumn, csvValueColumn}
+	csvProjectColumns   = []string{csvLevelColumn, csvTitleColumn, csvStartColumn, csvEndColumn, csvPercentageCol, csvColorColumn, csvURLsColumn, csvMilestoneColumn, csvPhasesColumn, csvWeightColumn, csvEstimateColumn, csvStatusColumn, csvOwnersColumn, csvLabelsColumn, csvFieldsColumn, csvIDColumn, csvAfterColumn, csvIncludeColumn, csvIncludedFromCol, csvDescriptionCol, csvCommentsColumn, csvCommentColumn}
+	csvMilestoneColumns = []string{csvKeyColumn, csvTitleColumn, csvDeadlineColumn, csvColorColumn, csvURLsColumn, csvFieldsColumn, csvIncludedFromCol, csvDescriptionCol, csvCommentsColumn, csvCommentColumn}
+)
+
+var (
+	errCSVMissingHeader = errors.New("row is not preceded by a header row, expected a header starting with setting, level or key")
+	errCSVMissingTitle  = errors.New("title is missing")
+	errCSVInvalidLevel  = errors.New("invalid level, expected a non-negative number")
+	errCSVInvalidStart  = errors.New("invalid start, expected a date, a period or a relative date (e.g. 2020-02-12, 2020-Q1, +2w)")
+	errCSVInvalidEnd    = errors.New("invalid end, expected a date, a period or a duration (e.g. 2020-02-12, 2020-Q1, 2w)")
+)
+
+// csvFormulaPrefix is added to cells which spreadsheets would otherwise read as formulas
+const csvFormulaPrefix = '\''
+
+// csvFormulaStarts are the characters which make spreadsheets read a cell as a formula
+const csvFormulaStarts = "=+-@\t\r"
+
+// needsCSVEscape returns true if a cell would be read as a formula or if it would lose its leading apostrophe on import
+func needsCSVEscape(cell string) bool {
+	if cell == "" {
+		return false
+	}
+
+	if strings.IndexByte(csvFormulaStarts, cell[0]) >= 0 {
+		return true
+	}
+
+	return cell[0] == csvFormulaPrefix && needsCSVEscape(cell[1:])
+}
+
+// csvEscapeCell prefixes a cell which spreadsheets would read as a formula with an apostrophe
+func csvEscapeCell(cell string) string {
+	if needsCSVEscape(cell) {
+		return string(csvFormulaPrefix) + cell
+	}
+
+	return cell
+}
+
+// csvUnescapeCell removes the apostrophe added by csvEscapeCell
+func csvUnescapeCell(cell string) string {
+	if cell != "" && cell[0] == csvFormulaPrefix && needsCSVEscape(cell[1:]) {
+		return cell[1:]
+	}
+
+	return cell
+}
+
+// csvSection represents a section of a CSV roadmap
+type csvSection string
+
+const (
+	csvSettings   csvSection = csvSettingColumn
+	csvProjects   csvSection = csvLevelColumn
+	csvMilestones csvSection = csvKeyColumn
+)
+
+// csvRow represents a row of a CSV roadmap with its cells mapped to the column names of its section
+type csvRow struct {
+	number  int
+	cells   map[string]string
+	columns map[string]int
+}
+
+// get returns the value of a cell as is, an empty string if the column is missing
+func (row csvRow) get(column string) string {
+	return row.cells[column]
+}
+
+// list returns the values of a cell holding one value per line, empty lines are kept, an empty cell has no values
+func (row csvRow) list(column string) []string {
+	if row.cells[column] == "" {
+		return nil
+	}
+
+	return strings.Split(row.cells[column], "\n")
+}
+
+// isIncluded returns true if the row holds a project or milestone of an included roadmap
+func (row csvRow) isIncluded() bool {
+	return row.get(csvIncludedFromCol) != ""
+}
+
+// column returns the number of a column (starting with 1) for diagnostics
+func (row csvRow) column(name string) int {
+	return row.columns[name] + 1
+}
+
+// ToCSV converts a Roadmap into CSV, the result can be converted back using ParseCSV without losing information
+func (r Roadmap) ToCSV() string {
+	records := [][]string{csvSettingColumns}
+
+	for _, c := range r.HeaderComments {
+		records = append(records, []string{csvHeaderComment, c})
+	}
+
+	for _, line := range r.headerLines() {
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) == 2 && !strings.HasPrefix(line, commentPrefix) {
+			records = append(records, parts)
+		}
+	}
+
+	for _, c := range r.Comments {
+		records = append(records, []string{csvCommentSetting, c})
+	}
+
+	records = append(records, nil, csvProjectColumns)
+
+	for _, p := range r.Projects {
+		records = append(records, p.withPositionalMilestone(r.Milestones).csvRecord(r.DateFormat))
+	}
+
+	records = append(records, nil, csvMilestoneColumns)
+
+	for _, m := range r.Milestones {
+		records = append(records, m.csvRecord(r.DateFormat))
+	}
+
+	for _, record := range records {
+		for i := range record {
+			record[i] = csvEscapeCell(record[i])
+		}
+	}
+
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	_ = w.WriteAll(records)
+
+	return buf.String()
+}
+
+// csvRecord converts a Project into a CSV record matching csvProjectColumns
+func (p Project) csvRecord(dateFormat string) []string {
+	var start, end string
+
+	if p.hasDateExpressions() {
+		start, end = p.StartExpression, p.EndExpression
+	} else if p.Dates != nil && !p.hasDerivedDates() {
+		start, end = formatDate(p.Dates.StartAt, dateFormat), formatDate(p.Dates.EndAt, dateFormat)
+	}
+
+	var percentage, weight, estimate, milestone, color string
+
+	if p.Percentage > 0 {
+		percentage = strconv.Itoa(int(p.Percentage))
+	}
+
+	if p.Weight > 0 {
+		weight = strconv.FormatFloat(p.Weight, 'f', -1, 64)
+	}
+
+	if p.Estimate > 0 {
+		estimate = strconv.FormatFloat(p.Estimate, 'f', -1, 64)
+	}
+
+	if p.MilestoneRef != "" {
+		milestone = p.MilestoneRef
+	} else if p.Milestone > 0 {
+		milestone = strconv.Itoa(int(p.Milestone))
+	}
+
+	if p.Color != nil {
+		color = colors.ToHexa(p.Color)
+	}
+
+	var phases []string
+	for _, ph := range p.Phases {
+		phases = append(phases, ph.String(dateFormat))
+	}
+
+	return []string{
+		strconv.Itoa(int(p.Indentation)),
+		p.Title,
+		start,
+		end,
+		percentage,
+		color,
+		strings.Join(p.URLs, "\n"),
+		milestone,
+		strings.Join(phases, "\n"),
+		weight,
+		estimate,
+		string(p.Status),
+		strings.Join(p.Owners, "\n"),
+		strings.Join(p.Labels, "\n"),
+		p.Fields.csvCell(),
+		p.ID,
+		strings.Join(p.DependsOn, "\n"),
+		p.Include,
+		p.IncludedFrom,
+		p.Description,
+		strings.Join(p.Comments, "\n"),
+		p.Comment,
+	}
+}
+
+// csvRecord converts a Milestone into a CSV record matching csvMilestoneColumns
+func (m Milestone) csvRecord(dateFormat string) []string {
+	var deadline, color string
+
+	if m.DeadlineAt != nil {
+		deadline = formatDate(*m.DeadlineAt, dateFormat)
+	}
+
+	if m.Color != nil {
+		color = colors.ToHexa(m.Color)
+	}
+
+	return []string{
+		m.Key,
+		m.Title,
+		deadline,
+		color,
+		strings.Join(m.URLs, "\n"),
+		m.Fields.csvCell(),
+		m.IncludedFrom,
+		m.Description,
+		strings.Join(m.Comments, "\n"),
+		m.Comment,
+	}
+}
+
+// csvCell converts fields into the content of a CSV cell, one field per line
+func (fs Fields) csvCell() string {
+	var lines []string
+
+	for _, f := range fs {
+		lines = append(lines, f.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ParseCSV converts a roadmap in CSV format into a Roadmap
+// settings found in the CSV are only used if no title, date format or base url is given, the date format is detected
+// from the dates found if neither is set
+func ParseCSV(content string, id uint64, prevID *uint64, title, dateFormat, baseUrl string, now time.Time) (Roadmap, Diagnostics) {
+	r := Roadmap{
+		ID:         id,
+		PrevID:     prevID,
+		Title:      title,
+		DateFormat: dateFormat,
+		BaseURL:    baseUrl,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		AccessedAt: now,
+	}
+
+	sections, ds := readCSVSections(content)
+
+	var s Settings
+
+	for _, row := range sections[csvSettings] {
+		key, value := strings.TrimSpace(row.get(csvSettingColumn)), row.get(csvValueColumn)
+
+		if key == csvCommentSetting {
+			r.Comments = append(r.Comments, value)
+			continue
+		}
+
+		if strings.EqualFold(key, csvHeaderComment) {
+			r.HeaderComments = append(r.HeaderComments, value)
+			continue
+		}
+
+		var err error
+
+		s, err = s.parseSetting(key + ": " + value)
+		if err != nil {
+			ds = append(ds, newDiagnostic(row.number, row.column(csvSettingColumn), err, key))
+		}
+	}
+
+	s.apply(&r)
+	r.DateFormat = normalizeDateFormat(r.DateFormat)
+
+	detection, detected := csvDateContent(sections).DetectDateFormat(), r.DateFormat == ""
+	if detected {
+		r.DateFormat = detection.formatOrDefault()
+	}
+	ds = append(ds, detection.diagnostics(r.DateFormat, detected)...)
+
+	var projectLines, milestoneLines []int
+
+	for _, row := range sections[csvProjects] {
+		if row.isIncluded() {
+			continue
+		}
+
+		p, ds2 := row.toProject(r.DateFormat, r.BaseURL)
+		ds = append(ds, ds2...)
+
+		r.Projects = append(r.Projects, p)
+		projectLines = append(projectLines, row.number)
+	}
+
+	for _, row := range sections[csvMilestones] {
+		if row.isIncluded() {
+			continue
+		}
+
+		m, ds2 := row.toMilestone(r.DateFormat, r.BaseURL)
+		ds = append(ds, ds2...)
+
+		r.Milestones = append(r.Milestones, m)
+		milestoneLines = append(milestoneLines, row.number)
+	}
+
+	loc := locator{projectLines: projectLines, milestoneLines: milestoneLines}
+	ds = append(ds, r.validateMilestoneReferences(loc)...)
+	ds = append(ds, r.validateDependencies(loc)...)
+	ds = append(ds, r.resolveRelativeDates(loc)...)
+
+	return r, ds.sorted()
+}
+
+// readCSVSections reads the rows of a CSV roadmap and groups them by section
+// empty rows are skipped, spreadsheets often export them as rows of empty cells
+func readCSVSections(content string) (map[csvSection][]csvRow, Diagnostics) {
+	var (
+		ds       Diagnostics
+		sections = map[csvSection][]csvRow{}
+		section  csvSection
+		header   []string
+	)
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			return sections, Diagnostics{{Line: pe.Line, Column: pe.Column, Severity: SeverityError, Message: pe.Err.Error()}}
+		}
+
+		return sections, Diagnostics{{Line: 1, Column: 1, Severity: SeverityError, Message: err.Error()}}
+	}
+
+	for i, record := range records {
+		if isEmptyCSVRecord(record) {
+			continue
+		}
+
+		for j := range record {
+			record[j] = csvUnescapeCell(record[j])
+		}
+
+		switch first := csvSection(strings.ToLower(strings.TrimSpace(record[0]))); first {
+		case csvSettings, csvProjects, csvMilestones:
+			section, header = first, record
+			ds = append(ds, checkCSVHeader(i+1, section, header)...)
+
+			continue
+		}
+
+		if section == "" {
+			ds = append(ds, newDiagnostic(i+1, 1, errCSVMissingHeader, record[0]))
+			continue
+		}
+
+		row := csvRow{number: i + 1, cells: map[string]string{}, columns: map[string]int{}}
+		for j, name := range header {
+			name = strings.ToLower(strings.TrimSpace(name))
+			row.columns[name] = j
+
+			if j < len(record) {
+				row.cells[name] = record[j]
+			}
+		}
+
+		sections[section] = append(sections[section], row)
+	}
+
+	return sections, ds
+}
+
+// checkCSVHeader warns about unknown columns, as their content is ignored
+func checkCSVHeader(number int, section csvSection, header []string) Diagnostics {
+	known := map[csvSection][]string{
+		csvSettings:   csvSettingColumns,
+		csvProjects:   csvProjectColumns,
+		csvMilestones: csvMilestoneColumns,
+	}[section]
+
+	var ds Diagnostics
+
+	for j, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || containsString(known, name) {
+			continue
+		}
+
+		ds = append(ds, Diagnostic{Line: number, Column: j + 1, Severity: SeverityWarning, Message: "unknown column, it will be ignored", Token: name})
+	}
+
+	return ds
+}
+
+// isEmptyCSVRecord returns true if all cells of a record are empty
+func isEmptyCSVRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// csvDateContent collects the cells containing dates into a Content which can be used for date format detection
+// each row ends up in the line of the same number, so that diagnostics refer to the right rows
+func csvDateContent(sections map[csvSection][]csvRow) Content {
+	var lines []string
+
+	add := func(row csvRow, columns ...string) {
+		for len(lines) < row.number {
+			lines = append(lines, "")
+		}
+
+		var values []string
+		for _, c := range columns {
+			for _, v := range row.list(c) {
+				if v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+
+		lines[row.number-1] = strings.Join(values, ", ")
+	}
+
+	for _, row := range sections[csvProjects] {
+		if !row.isIncluded() {
+			add(row, csvStartColumn, csvEndColumn, csvPhasesColumn)
+		}
+	}
+
+	for _, row := range sections[csvMilestones] {
+		if !row.isIncluded() {
+			add(row, csvDeadlineColumn)
+		}
+	}
+
+	return Content(strings.Join(lines, "\n"))
+}
+
+// csvPart represents a value of a CSV cell converted into the extra part of a line, see parseExtraPart
+type csvPart struct {
+	column string
+	part   string
+}
+
+// extraParts converts the cells of a row into the parts of an extra, adding the prefixes needed to recognize them
+func (row csvRow) extraParts(columns ...string) []csvPart {
+	prefixes := map[string]string{
+		csvMilestoneColumn: "|",
+		csvWeightColumn:    weightPrefix,
+		csvEstimateColumn:  estimatePrefix,
+		csvOwnersColumn:    ownerPrefix,
+		csvLabelsColumn:    labelPrefix,
+		csvIDColumn:        idPrefix,
+		csvKeyColumn:       idPrefix,
+		csvAfterColumn:     afterPrefix,
+		csvIncludeColumn:   includePrefix,
+		csvColorColumn:     "#",
+	}
+
+	var parts []csvPart
+
+	for _, c := range columns {
+		for _, v := range row.list(c) {
+			if v == "" {
+				continue
+			}
+
+			if prefix := prefixes[c]; !strings.HasPrefix(v, prefix) {
+				v = prefix + v
+			}
+
+			if c == csvPercentageCol && !strings.HasSuffix(v, "%") {
+				v += "%"
+			}
+
+			parts = append(parts, csvPart{column: c, part: v})
+		}
+	}
+
+	return parts
+}
+
+// parseExtra parses the cells of a row the same way as the extra part of a line is parsed
+func (row csvRow) parseExtra(dateFormat, baseUrl string, columns ...string) (extraData, Diagnostics) {
+	var (
+		e  extraData
+		ds Diagnostics
+	)
+
+	for _, p := range row.extraParts(columns...) {
+		var err error
+
+		e, err = parseExtraPart(p.part, e, dateFormat, baseUrl)
+		if err != nil {
+			ds = append(ds, newDiagnostic(row.number, row.column(p.column), err, p.part))
+		}
+	}
+
+	return e, ds
+}
+
+// parseDates parses the start and the end of a project, each of them by their own column, so that a row with an end but
+// without a start does not end up with the end used as start
+// an end date without a start is kept as an expression, the project starts after its dependencies if it has any
+func (row csvRow) parseDates(e extraData, dateFormat string) (extraData, Diagnostics) {
+	var (
+		ds         Diagnostics
+		start, end = strings.TrimSpace(row.get(csvStartColumn)), strings.TrimSpace(row.get(csvEndColumn))
+	)
+
+	if start != "" {
+		s, err := parseExtraPart(start, extraData{}, dateFormat, "")
+		switch {
+		case err != nil:
+			ds = append(ds, newDiagnostic(row.number, row.column(csvStartColumn), err, start))
+		case s.startAt == nil && s.startExpr == "":
+			ds = append(ds, newDiagnostic(row.number, row.column(csvStartColumn), errCSVInvalidStart, start))
+		default:
+			e.startAt, e.startExpr = s.startAt, s.startExpr
+		}
+	}
+
+	if end != "" {
+		// a start is set, so that dates and periods are read as the end
+		x, err := parseExtraPart(end, extraData{startExpr: "^"}, dateFormat, "")
+		switch {
+		case err != nil && !errors.Is(err, errStartDateAlreadySet):
+			ds = append(ds, newDiagnostic(row.number, row.column(csvEndColumn), err, end))
+		case x.endAt == nil && x.endExpr == "":
+			ds = append(ds, newDiagnostic(row.number, row.column(csvEndColumn), errCSVInvalidEnd, end))
+		default:
+			e.endAt, e.endExpr = x.endAt, x.endExpr
+		}
+	}
+
+	if e.startAt == nil && e.startExpr == "" && e.endAt != nil {
+		e.endExpr, e.endAt = formatDate(*e.endAt, dateFormat), nil
+	}
+
+	return e, ds
+}
+
+// toProject converts a row of the projects section into a Project
+func (row csvRow) toProject(dateFormat, baseUrl string) (Project, Diagnostics) {
+	var ds Diagnostics
+
+	level, err := strconv.ParseUint(row.get(csvLevelColumn), 10, 8)
+	if err != nil && row.get(csvLevelColumn) != "" {
+		ds = append(ds, newDiagnostic(row.number, row.column(csvLevelColumn), errCSVInvalidLevel, row.get(csvLevelColumn)))
+	}
+
+	title := row.get(csvTitleColumn)
+	if title == "" {
+		ds = append(ds, newDiagnostic(row.number, row.column(csvTitleColumn), errCSVMissingTitle, ""))
+	}
+
+	e, ds2 := row.parseExtra(dateFormat, baseUrl, csvPhasesColumn, csvPercentageCol, csvWeightColumn, csvEstimateColumn, csvStatusColumn, csvOwnersColumn, csvLabelsColumn, csvFieldsColumn, csvColorColumn, csvURLsColumn, csvMilestoneColumn, csvIDColumn, csvAfterColumn, csvIncludeColumn)
+	ds = append(ds, ds2...)
+
+	e, ds2 = row.parseDates(e, dateFormat)
+	ds = append(ds, ds2...)
+
+	p := e.toProject(uint8(level), title, dateFormat)
+	if e.startAt != nil && e.endAt != nil && p.Dates != nil && p.Dates.EndAt.Before(p.Dates.StartAt) {
+		ds = append(ds, Diagnostic{Line: row.number, Column: row.column(csvEndColumn), Severity: SeverityError, Message: "end date is before start date", Token: row.get(csvEndColumn)})
+	}
+
+	p.Description = row.get(csvDescriptionCol)
+	p.Comments = row.list(csvCommentsColumn)
+	p.Comment = row.get(csvCommentColumn)
+
+	return p, ds
+}
+
+// toMilestone converts a row of the milestones section into a Milestone
+func (row csvRow) toMilestone(dateFormat, baseUrl string) (Milestone, Diagnostics) {
+	var ds Diagnostics
+
+	title := row.get(csvTitleColumn)
+	if title == "" {
+		ds = append(ds, newDiagnostic(row.number, row.column(csvTitleColumn), errCSVMissingTitle, ""))
+	}
+
+	e, ds2 := row.parseExtra(dateFormat, baseUrl, csvDeadlineColumn, csvColorColumn, csvURLsColumn, csvFieldsColumn, csvKeyColumn)
+	ds = append(ds, ds2...)
+
+	if e.hasProjectData() {
+		ds = append(ds, Diagnostic{Line: row.number, Column: 1, Severity: SeverityWarning, Message: errMilestoneProjectData.Error()})
+	}
+
+	m := e.toMilestone(title)
+	m.Description = row.get(csvDescriptionCol)
+	m.Comments = row.list(csvCommentsColumn)
+	m.Comment = row.get(csvCommentColumn)
+
+	return m, ds
+}
@@ -0,0 +1,218 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoadmap_ToCSV(t *testing.T) {
+	c := Content(`---
+// maintained by the platform team
+title: Launch
+dateFormat: 02.01.2006
+---
+Backend [id:backend, ~api]
+	API [01.02.2020, 15.02.2020, 100%, weight=2, @alice, team=payments, #f00, https://example.com/api, |beta, id:api-task]
+	Storage [+1w, 2w, after:api-task, id:storage]
+
+|Beta [01.03.2020, id:beta]`)
+
+	r, ds := c.Parse(1, nil, "", "", "", time.Now())
+	require.False(t, ds.HasErrors(), ds.Error())
+
+	want := `setting,value
+headerComment,maintained by the platform team
+title,Launch
+dateFormat,02.01.2006
+
+level,title,start,end,percentage,color,urls,milestone,phases,weight,estimate,status,owners,labels,fields,id,after,include,included_from,description,comments,comment
+0,Backend,,,,,,,,,,,,api,,backend,,,,,,
+1,API,01.02.2020,15.02.2020,100,#ff0000,https://example.com/api,beta,,2,,,alice,,team=payments,api-task,,,,,,
+1,Storage,'+1w,2w,,,,,,,,,,,,storage,api-task,,,,,
+
+key,title,deadline,color,urls,fields,included_from,description,comments,comment
+beta,Beta,01.03.2020,,,,,,,
+`
+
+	assert.Equal(t, want, r.ToCSV())
+}
+
+func TestParseCSV_RoundTrip(t *testing.T) {
+	c := Content(`---
+// maintained by the platform team
+title: Launch
+dateFormat: 02.01.2006
+baseUrl: https://example.com/
+timezone: Europe/Berlin
+---
+// Backend work
+Backend [id:backend, ~api, ~core, at-risk] // owned by the platform team
+	API [01.02.2020 09:00, 15.02.2020, 60%, weight=2, estimate=8, @alice, @bob, team=payments, #f00, https://example.com/api, /docs, |beta, id:api]
+		> Public endpoints, including
+		> the admin ones
+	Storage [+1w, 2w, after:api, id:storage]
+	Design [Mockups:01.02.2020..07.02.2020, Review:08.02.2020..10.02.2020]
+Included [include:abc123]
+
+|Beta [01.03.2020, #00f, https://example.com/beta, stage=beta, id:beta]
+|Launch
+// the end`)
+
+	r, ds := c.Parse(1, nil, "", "", "", time.Now())
+	require.False(t, ds.HasErrors(), ds.Error())
+
+	parsed, ds := ParseCSV(r.ToCSV(), 1, nil, "", "", "", r.CreatedAt)
+	require.False(t, ds.HasErrors(), ds.Error())
+	assert.Empty(t, ds)
+
+	assert.Equal(t, "Public endpoints, including\nthe admin ones", parsed.Projects[1].Description)
+	assert.Equal(t, r, parsed)
+	assert.Equal(t, r.String(), parsed.String())
+}
+
+func TestParseCSV_RoundTrip_included(t *testing.T) {
+	r := Roadmap{
+		DateFormat: "2006-01-02",
+		Projects: []Project{
+			{Title: "Department", Include: "teamA"},
+			{Title: "Backend", Indentation: 1, ID: "teamA:0", Milestone: 2, IncludedFrom: "teamA"},
+			{Title: "Frontend", Milestone: 1},
+		},
+		Milestones: []Milestone{
+			{Title: "Launch"},
+			{Title: "Beta", Key: "beta", IncludedFrom: "teamA"},
+		},
+	}
+
+	csv := r.ToCSV()
+	assert.Contains(t, csv, "1,Backend,,,,,,2,,,,,,,,teamA:0,,,teamA,,,\n")
+
+	parsed, ds := ParseCSV(csv, 0, nil, "", "", "", time.Now())
+	require.False(t, ds.HasErrors(), ds.Error())
+
+	assert.Equal(t, r.withoutIncluded().Projects, parsed.Projects)
+	assert.Equal(t, r.withoutIncluded().Milestones, parsed.Milestones)
+}
+
+func TestParseCSV_RoundTrip_verbatim(t *testing.T) {
+	r := Roadmap{
+		DateFormat: "2006-01-02",
+		Comments:   []string{" first", "", "last "},
+		Projects: []Project{
+			{Title: "  Indented title", Comments: []string{"", "  code sample", ""}, Comment: " spaced ", Description: "  leading\n\ntrailing  "},
+		},
+		Milestones: []Milestone{
+			{Title: "Beta ", Comments: []string{"before", ""}},
+		},
+	}
+
+	parsed, ds := ParseCSV(r.ToCSV(), 0, nil, "", "", "", time.Now())
+	require.False(t, ds.HasErrors(), ds.Error())
+
+	assert.Equal(t, r.Comments, parsed.Comments)
+	assert.Equal(t, r.Projects, parsed.Projects)
+	assert.Equal(t, r.Milestones, parsed.Milestones)
+}
+
+func TestParseCSV_RoundTrip_formulas(t *testing.T) {
+	r := Roadmap{
+		Title:      "Launch",
+		DateFormat: "2006-01-02",
+		Projects: []Project{
+			{Title: "=SUM(A1:A2)", StartExpression: "+2w", EndExpression: "3d"},
+			{Title: "-3d buffer", Indentation: 1, StartExpression: "-3d", EndExpression: "1w"},
+			{Title: "@home", Indentation: 1, StartExpression: "^+1w", EndExpression: "1d", Comment: "+1"},
+			{Title: "'=quoted", Indentation: 1},
+			{Title: "'plain"},
+		},
+		Milestones: []Milestone{{Title: "+launch"}},
+	}
+
+	content := r.ToCSV()
+	assert.Contains(t, content, "'=SUM(A1:A2),'+2w,3d")
+	assert.Contains(t, content, "'-3d buffer,'-3d,1w")
+	assert.Contains(t, content, "'@home,^+1w")
+	assert.Contains(t, content, "''=quoted")
+	assert.Contains(t, content, ",'plain,")
+
+	parsed, _ := ParseCSV(content, 0, nil, "", "", "", time.Time{})
+
+	for i := range parsed.Projects {
+		parsed.Projects[i].Dates = nil
+	}
+	assert.Equal(t, r.Projects, parsed.Projects)
+	assert.Equal(t, r.Milestones, parsed.Milestones)
+}
+
+func TestParseCSV_dates(t *testing.T) {
+	content := `level,title,start,end,id,after
+0,API,2020-02-01,2020-02-14,api,
+0,Storage,,2020-02-29,,api
+0,Docs,,2020-03-15,,
+0,Review,1w,,,`
+
+	r, ds := ParseCSV(content, 1, nil, "", "2006-01-02", "", time.Now())
+
+	require.Len(t, r.Projects, 4)
+	assert.Equal(t, &Dates{StartAt: time.Date(2020, 2, 15, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)}, r.Projects[1].Dates)
+	assert.Equal(t, "", r.Projects[2].StartExpression)
+	assert.Equal(t, "2020-03-15", r.Projects[2].EndExpression)
+	assert.Nil(t, r.Projects[2].Dates)
+	assert.Equal(t, Diagnostics{
+		{Line: 4, Column: 1, Severity: SeverityWarning, Message: "dates could not be calculated, a start and an end are needed and relative dates need a parent, a previous sibling or a dependency with dates", Token: "2020-03-15"},
+		{Line: 5, Column: 3, Severity: SeverityError, Message: errCSVInvalidStart.Error(), Token: "1w"},
+	}, ds)
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantTitles []string
+		wantDiag   []Diagnostic
+	}{
+		{
+			"spreadsheet export with reordered and missing columns",
+			`level,title,end,start,,unknown
+0,Project,2020-02-15,2020-02-01,,x
+1,Sub-project,1w,+0d,,
+,,,,,
+key,title,deadline
+,Release,2020-03-01`,
+			[]string{"Project", "Sub-project"},
+			[]Diagnostic{
+				{Line: 1, Column: 6, Severity: SeverityWarning, Message: "unknown column, it will be ignored", Token: "unknown"},
+			},
+		},
+		{
+			"errors",
+			`Project,2020-02-01
+level,title,start,end,percentage
+x,,2020-02-01,2020-01-15,150`,
+			[]string{""},
+			[]Diagnostic{
+				{Line: 1, Column: 1, Severity: SeverityError, Message: errCSVMissingHeader.Error(), Token: "Project"},
+				{Line: 3, Column: 1, Severity: SeverityError, Message: errCSVInvalidLevel.Error(), Token: "x"},
+				{Line: 3, Column: 2, Severity: SeverityError, Message: errCSVMissingTitle.Error()},
+				{Line: 3, Column: 4, Severity: SeverityError, Message: "end date is before start date", Token: "2020-01-15"},
+				{Line: 3, Column: 5, Severity: SeverityError, Message: errCannotParsePercentage.Error(), Token: "150%"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ds := ParseCSV(tt.content, 1, nil, "", "", "", time.Now())
+
+			var titles []string
+			for _, p := range r.Projects {
+				titles = append(titles, p.Title)
+			}
+
+			assert.Equal(t, tt.wantTitles, titles)
+			assert.Equal(t, Diagnostics(tt.wantDiag), ds)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package roadmap
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// dateTokenRegexp finds date-like strings within a line, see isDateLike
+var dateTokenRegexp = regexp.MustCompile(`\b\d{1,4}[-./]\d{1,2}[-./]\d{1,4}\b`)
+
+// dateToken represents a date-like string found in a Content
+type dateToken struct {
+	Line   int
+	Column int
+	Value  string
+}
+
+// DateFormatDetection represents the result of scoring the known date formats against the dates of a roadmap
+type DateFormatDetection struct {
+	// Format is the date format matching the most dates, empty if no date-like strings were found
+	Format string
+	// Matches is the number of dates Format could parse
+	Matches int
+	// Total is the number of date-like strings found
+	Total int
+	// Alternatives are date formats matching just as many dates, but reading them differently (e.g. 01/02 vs 02/01)
+	Alternatives []string
+
+	tokens []dateToken
+}
+
+// IsAmbiguous returns true if other date formats would match the dates just as well, but read them differently
+func (d DateFormatDetection) IsAmbiguous() bool {
+	return len(d.Alternatives) > 0
+}
+
+// formatOrDefault returns the detected date format or the default date format if no dates were found
+func (d DateFormatDetection) formatOrDefault() string {
+	if d.Format == "" {
+		return defaultDateFormat
+	}
+
+	return d.Format
+}
+
+// DetectDateFormat scores the known date formats against the date-like strings found in Content
+func (c Content) DetectDateFormat() DateFormatDetection {
+	var tokens []dateToken
+
+	for i, line := range c.ToLines() {
+		for _, loc := range dateTokenRegexp.FindAllStringIndex(line, -1) {
+			tokens = append(tokens, dateToken{Line: i + 1, Column: loc[0] + 1, Value: line[loc[0]:loc[1]]})
+		}
+	}
+
+	return detectDateFormat(tokens)
+}
+
+// detectDateFormat scores the date-like strings found in the expressions of projects
+// it is used for roadmaps not created from a Content, therefore the tokens have no position
+func (r Roadmap) detectDateFormat() DateFormatDetection {
+	var tokens []dateToken
+
+	for _, p := range r.Projects {
+		for _, expr := range []string{p.StartExpression, p.EndExpression} {
+			for _, v := range dateTokenRegexp.FindAllString(expr, -1) {
+				tokens = append(tokens, dateToken{Value: v})
+			}
+		}
+	}
+
+	return detectDateFormat(tokens)
+}
+
+// detectDateFormat chooses the date format parsing the most tokens, the order of dateFormats decides ties
+// formats parsing as many tokens, but to different dates, are reported as alternatives
+func detectDateFormat(tokens []dateToken) DateFormatDetection {
+	d := DateFormatDetection{Total: len(tokens), tokens: tokens}
+	if len(tokens) == 0 {
+		return d
+	}
+
+	for _, f := range dateFormats {
+		if m := countDateMatches(f, tokens); m > d.Matches {
+			d.Format, d.Matches = f, m
+		}
+	}
+
+	if d.Format == "" {
+		return d
+	}
+
+	readings := []string{readDates(d.Format, tokens)}
+
+	for _, f := range dateFormats {
+		if countDateMatches(f, tokens) != d.Matches {
+			continue
+		}
+
+		reading := readDates(f, tokens)
+		if containsString(readings, reading) {
+			continue
+		}
+
+		readings = append(readings, reading)
+		d.Alternatives = append(d.Alternatives, f)
+	}
+
+	return d
+}
+
+// countDateMatches returns the number of tokens a date format can parse
+func countDateMatches(dateFormat string, tokens []dateToken) int {
+	var n int
+
+	for _, t := range tokens {
+		if _, err := time.Parse(dateFormat, t.Value); err == nil {
+			n++
+		}
+	}
+
+	return n
+}
+
+// readDates returns the dates parsed from tokens using a date format, so that formats can be compared
+func readDates(dateFormat string, tokens []dateToken) string {
+	var dates []string
+
+	for _, t := range tokens {
+		if d, err := time.Parse(dateFormat, t.Value); err == nil {
+			dates = append(dates, d.Format(defaultDateFormat))
+		}
+	}
+
+	return strings.Join(dates, ",")
+}
+
+// containsString returns true if a string is found in a slice of strings
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// legacyDateFormats maps date formats offered by earlier versions to the formats they were meant to be
+// they contained the year 2020 instead of 2006 by mistake, therefore they could only parse dates in 2020
+var legacyDateFormats = map[string]string{
+	"01/02/2020": "01/02/2006",
+	"01.02.2020": "01.02.2006",
+	"1/2/2020":   "1/2/2006",
+	"1.2.2020":   "1.2.2006",
+}
+
+// normalizeDateFormat replaces legacy date formats with the ones they were meant to be, see legacyDateFormats
+func normalizeDateFormat(dateFormat string) string {
+	if f, ok := legacyDateFormats[dateFormat]; ok {
+		return f
+	}
+
+	return dateFormat
+}
+
+// describeDateFormat returns the human readable version of a date format if known
+func describeDateFormat(dateFormat string) string {
+	if desc, ok := dateFormatMap[dateFormat]; ok {
+		return desc
+	}
+
+	return dateFormat
+}
+
+// diagnostics returns warnings about the detection, given the date format actually used for parsing
+// if the date format used was chosen by the detection, ambiguity is reported, otherwise a better matching format is
+// suggested, an ambiguous date format chosen explicitly is not reported
+func (d DateFormatDetection) diagnostics(dateFormat string, detected bool) Diagnostics {
+	if d.Format == "" {
+		return nil
+	}
+
+	if detected {
+		if !d.IsAmbiguous() {
+			return nil
+		}
+
+		var alternatives []string
+		for _, f := range d.Alternatives {
+			alternatives = append(alternatives, describeDateFormat(f))
+		}
+
+		t := d.tokens[0]
+		msg := fmt.Sprintf(
+			"date format is ambiguous, %s was chosen, but %s would also match",
+			describeDateFormat(d.Format),
+			strings.Join(alternatives, ", "),
+		)
+
+		return Diagnostics{{Line: t.Line, Column: t.Column, Severity: SeverityWarning, Message: msg, Token: t.Value}}
+	}
+
+	if dateFormat == d.Format {
+		return nil
+	}
+
+	matches := countDateMatches(dateFormat, d.tokens)
+	if matches >= d.Matches {
+		return nil
+	}
+
+	for _, t := range d.tokens {
+		if _, err := time.Parse(dateFormat, t.Value); err == nil {
+			continue
+		}
+
+		msg := fmt.Sprintf(
+			"only %d of %d dates match the date format %s, %s would match %d",
+			matches,
+			d.Total,
+			describeDateFormat(dateFormat),
+			describeDateFormat(d.Format),
+			d.Matches,
+		)
+
+		return Diagnostics{{Line: t.Line, Column: t.Column, Severity: SeverityWarning, Message: msg, Token: t.Value}}
+	}
+
+	return nil
+}
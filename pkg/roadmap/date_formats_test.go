@@ -0,0 +1,194 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContent_DetectDateFormat(t *testing.T) {
+	tests := []struct {
+		name             string
+		c                Content
+		wantFormat       string
+		wantMatches      int
+		wantTotal        int
+		wantAlternatives []string
+	}{
+		{
+			"no dates",
+			"Project\n\tSub-project",
+			"",
+			0,
+			0,
+			nil,
+		},
+		{
+			"iso dates",
+			"Project [2020-02-01, 2020-02-14]\n|Milestone [2020-03-01]",
+			"2006-01-02",
+			3,
+			3,
+			nil,
+		},
+		{
+			"day first is unambiguous if a day is above 12",
+			"Project [01/02/2020, 17/02/2020]",
+			"02/01/2006",
+			2,
+			2,
+			nil,
+		},
+		{
+			"month first is unambiguous if a day is above 12",
+			"Project [02/01/2020, 02/17/2020]",
+			"01/02/2006",
+			2,
+			2,
+			nil,
+		},
+		{
+			"ambiguous dates",
+			"Project [01/02/2020, 03/04/2020]",
+			"02/01/2006",
+			2,
+			2,
+			[]string{"01/02/2006"},
+		},
+		{
+			"phases",
+			"Project [Design:2020.02.01..2020.02.14]",
+			"2006.01.02",
+			2,
+			2,
+			nil,
+		},
+		{
+			"best match wins",
+			"Project [2020-02-01, 2020-02-14]\nProject 2 [2020/02/01]",
+			"2006-01-02",
+			2,
+			3,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.c.DetectDateFormat()
+
+			assert.Equal(t, tt.wantFormat, got.Format)
+			assert.Equal(t, tt.wantMatches, got.Matches)
+			assert.Equal(t, tt.wantTotal, got.Total)
+			assert.Equal(t, tt.wantAlternatives, got.Alternatives)
+		})
+	}
+}
+
+func TestContent_Parse_dateFormatDetection(t *testing.T) {
+	now := time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		c              Content
+		dateFormat     string
+		wantDateFormat string
+		wantDates      *Dates
+		wantMessages   []string
+	}{
+		{
+			"detected",
+			"Project [17.02.2020, 28.02.2020]",
+			"",
+			"02.01.2006",
+			&Dates{StartAt: time.Date(2020, 2, 17, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 28, 0, 0, 0, 0, time.UTC)},
+			nil,
+		},
+		{
+			"default without dates",
+			"Project",
+			"",
+			"2006-01-02",
+			nil,
+			nil,
+		},
+		{
+			"ambiguous",
+			"Project [01/02/2020, 03/04/2020]",
+			"",
+			"02/01/2006",
+			&Dates{StartAt: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC)},
+			[]string{"date format is ambiguous, DD/MM/YYYY (17/03/2020) was chosen, but MM/DD/YYYY (03/17/2020) would also match"},
+		},
+		{
+			"given format is kept",
+			"Project [01/02/2020, 03/04/2020]",
+			"01/02/2006",
+			"01/02/2006",
+			&Dates{StartAt: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC)},
+			nil,
+		},
+		{
+			"ambiguous format given is not reported",
+			"Project [01/02/2020, 03/04/2020]",
+			"02/01/2006",
+			"02/01/2006",
+			&Dates{StartAt: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC)},
+			nil,
+		},
+		{
+			"better format is suggested",
+			"Project [2020/02/01, 2020/02/14]",
+			"2006-01-02",
+			"2006-01-02",
+			nil,
+			[]string{
+				"only 0 of 2 dates match the date format YYYY-MM-DD (2020-03-17), YYYY/MM/DD (2020/03/17) would match 2",
+				"invalid date, expected format: 2006-01-02",
+				"invalid date, expected format: 2006-01-02",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ds := tt.c.Parse(0, nil, "", tt.dateFormat, "", now)
+
+			var messages []string
+			for _, d := range ds {
+				messages = append(messages, d.Message)
+			}
+
+			assert.Equal(t, tt.wantDateFormat, r.DateFormat)
+			require.Len(t, r.Projects, 1)
+			assert.Equal(t, tt.wantDates, r.Projects[0].Dates)
+			assert.Equal(t, tt.wantMessages, messages)
+		})
+	}
+}
+
+func Test_normalizeDateFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		dateFormat string
+		want       string
+	}{
+		{"empty", "", ""},
+		{"valid format", "02/01/2006", "02/01/2006"},
+		{"legacy format", "01/02/2020", "01/02/2006"},
+		{"legacy format without leading zeros", "1.2.2020", "1.2.2006"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizeDateFormat(tt.dateFormat))
+		})
+	}
+}
+
+func TestContent_Parse_legacyDateFormat(t *testing.T) {
+	r, ds := Content("Backend [03/17/2021, 04/01/2021]").Parse(0, nil, "", "01/02/2020", "", time.Now())
+
+	assert.False(t, ds.HasErrors(), ds.Error())
+	assert.Equal(t, "01/02/2006", r.DateFormat)
+	assert.Equal(t, time.Date(2021, 3, 17, 0, 0, 0, 0, time.UTC), r.Projects[0].Dates.StartAt)
+}
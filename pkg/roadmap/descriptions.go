@@ -0,0 +1,205 @@
+package roadmap
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// descriptionMarker starts a continuation line holding the description of the preceding project or milestone
+// (e.g. "	> We need a domain before we can set up emails")
+const descriptionMarker = ">"
+
+// splitDescription returns the text of a description line
+func splitDescription(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, descriptionMarker) {
+		return "", false
+	}
+
+	text := strings.TrimPrefix(trimmed[len(descriptionMarker):], " ")
+
+	return strings.TrimRight(text, " \t\r"), true
+}
+
+// extractDescriptions removes all description lines from a Content
+// description lines are replaced by empty lines, so that line numbers don't change
+func (c Content) extractDescriptions() (Content, map[int]string) {
+	descriptions := map[int]string{}
+
+	lines := c.ToLines()
+
+	for i, line := range lines {
+		text, found := splitDescription(line)
+		if !found {
+			continue
+		}
+
+		lines[i] = ""
+		descriptions[i] = text
+	}
+
+	return Content(strings.Join(lines, "\n")), descriptions
+}
+
+// attachDescriptions stores description lines on the project or milestone preceding them
+// consecutive description lines are joined by new lines
+func (r *Roadmap) attachDescriptions(c Content, descriptions map[int]string) Diagnostics {
+	var (
+		ds           Diagnostics
+		project      *Project
+		milestone    *Milestone
+		projectIdx   int
+		milestoneIdx int
+	)
+
+	for i, line := range c.ToLines() {
+		if text, ok := descriptions[i]; ok {
+			switch {
+			case milestone != nil:
+				milestone.Description = appendDescription(milestone.Description, text)
+			case project != nil:
+				project.Description = appendDescription(project.Description, text)
+			default:
+				ds = append(ds, Diagnostic{Line: i + 1, Column: 1, Severity: SeverityWarning, Message: "description must follow a project or a milestone", Token: text})
+			}
+
+			continue
+		}
+
+		if isLineProject(line) && projectIdx < len(r.Projects) {
+			project, milestone = &r.Projects[projectIdx], nil
+			projectIdx++
+
+			continue
+		}
+
+		if isLineMilestone(line) && milestoneIdx < len(r.Milestones) {
+			project, milestone = nil, &r.Milestones[milestoneIdx]
+			milestoneIdx++
+		}
+	}
+
+	return ds
+}
+
+// appendDescription adds a line to a description
+func appendDescription(description, text string) string {
+	if description == "" {
+		return text
+	}
+
+	return description + "\n" + text
+}
+
+// withDescription adds the description of a project or milestone to the line(s) representing it
+func withDescription(line, indentation, description string) string {
+	if description == "" {
+		return line
+	}
+
+	lines := []string{line}
+
+	for _, d := range strings.Split(description, "\n") {
+		if d == "" {
+			lines = append(lines, indentation+"\t"+descriptionMarker)
+
+			continue
+		}
+
+		lines = append(lines, indentation+"\t"+descriptionMarker+" "+d)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// describedItem represents a project or milestone with a description, used in the HTML view
+type describedItem struct {
+	Title       string
+	Description string
+}
+
+// getDescriptions collects the projects and milestones with a description
+func (r *Roadmap) getDescriptions() []describedItem {
+	var items []describedItem
+
+	if r == nil {
+		return items
+	}
+
+	for _, p := range r.Projects {
+		if p.Description != "" {
+			items = append(items, describedItem{Title: p.Title, Description: p.Description})
+		}
+	}
+
+	for _, m := range r.Milestones {
+		if m.Description != "" {
+			items = append(items, describedItem{Title: m.Title, Description: m.Description})
+		}
+	}
+
+	return items
+}
+
+// AddTooltips adds the descriptions of projects and milestones to an SVG image as <title> elements, which are
+// displayed as tooltips by browsers
+// other image formats are returned unchanged
+func (vr *VisualRoadmap) AddTooltips(img []byte, fileFormat FileFormat, fullW, lineH float64) []byte {
+	if fileFormat != SvgFormat {
+		return img
+	}
+
+	// SVG coordinates grow from the top of the image, unlike the canvas coordinates used for drawing
+	headerH := vr.headerHeight(lineH)
+	fullH := vr.imageHeight(lineH)
+
+	var tooltips []string
+
+	for i, p := range vr.Projects {
+		if p.Description == "" {
+			continue
+		}
+
+		y := fullH - rowTop(fullH, headerH, lineH, i)
+		tooltips = append(tooltips, svgTooltip(0, y, fullW, lineH, p.Description))
+	}
+
+	if vr.Dates != nil {
+		for _, m := range vr.Milestones {
+			if m.Description == "" || m.DeadlineAt == nil {
+				continue
+			}
+
+			x := vr.dateX(*m.DeadlineAt, fullW)
+			tooltips = append(tooltips, svgTooltip(x-lineH/2, 0, lineH, fullH, m.Description))
+		}
+	}
+
+	if len(tooltips) == 0 {
+		return img
+	}
+
+	i := bytes.LastIndex(img, []byte("</svg>"))
+	if i < 0 {
+		return img
+	}
+
+	result := append([]byte{}, img[:i]...)
+	result = append(result, strings.Join(tooltips, "")...)
+
+	return append(result, img[i:]...)
+}
+
+// svgTooltip creates an invisible rectangle with a <title> element
+func svgTooltip(x, y, w, h float64, text string) string {
+	return fmt.Sprintf(
+		`<rect x="%.5g" y="%.5g" width="%.5g" height="%.5g" fill="#000" fill-opacity="0"><title>%s</title></rect>`,
+		x,
+		y,
+		w,
+		h,
+		html.EscapeString(text),
+	)
+}
@@ -0,0 +1,105 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_splitDescription(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		want  string
+		want1 bool
+	}{
+		{"project", "Bring website online", "", false},
+		{"description", "\t> We need a domain first", "We need a domain first", true},
+		{"empty description", "\t>", "", true},
+		{"escaped marker", `\> foo`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := splitDescription(tt.line)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+		})
+	}
+}
+
+func TestContent_Parse_descriptions(t *testing.T) {
+	const content = `Bring website online
+	> Everything needed to go live.
+	Select and purchase domain [2020-02-12, 2020-02-20]
+		> We need a domain before we can set up emails.
+		>
+		> Ask ops // they know the registrar
+	Create server infrastructure
+
+|Milestone 0.1 [2020-03-01]
+	> First public release`
+
+	r, ds := Content(content).Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Len(t, r.Projects, 3)
+	assert.Equal(t, "Everything needed to go live.", r.Projects[0].Description)
+	assert.Equal(t, "We need a domain before we can set up emails.\n\nAsk ops // they know the registrar", r.Projects[1].Description)
+	assert.Empty(t, r.Projects[1].Comment)
+	assert.Empty(t, r.Projects[2].Description)
+	assert.Equal(t, "First public release", r.Milestones[0].Description)
+
+	assert.Equal(t, content, r.String())
+}
+
+func TestContent_Parse_descriptionWithoutProject(t *testing.T) {
+	r, ds := Content("> Orphan\nBring website online").Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Len(t, r.Projects, 1)
+	assert.Empty(t, r.Projects[0].Description)
+	assert.Equal(t, Diagnostics{{Line: 1, Column: 1, Severity: SeverityWarning, Message: "description must follow a project or a milestone", Token: "Orphan"}}, ds)
+}
+
+func TestVisualRoadmap_AddTooltips(t *testing.T) {
+	startAt := time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+	endAt := time.Date(2020, 2, 20, 0, 0, 0, 0, time.UTC)
+
+	vr := &VisualRoadmap{
+		Dates: &Dates{StartAt: startAt, EndAt: endAt},
+		Projects: []Project{
+			{Title: "Bring website online", Dates: &Dates{StartAt: startAt, EndAt: endAt}},
+			{Title: "Select and purchase domain", Indentation: 1, Dates: &Dates{StartAt: startAt, EndAt: endAt}, Description: "Needs <budget> & approval"},
+		},
+		Milestones: []Milestone{
+			{Title: "Milestone 0.1", DeadlineAt: &endAt, Description: "First release"},
+		},
+	}
+
+	img := []byte(`<svg></svg>`)
+
+	t.Run("png is not changed", func(t *testing.T) {
+		assert.Equal(t, img, vr.AddTooltips(img, PngFormat, 800, 40))
+	})
+
+	t.Run("svg gets titles", func(t *testing.T) {
+		got := string(vr.AddTooltips(img, SvgFormat, 900, 40))
+
+		assert.True(t, strings.HasPrefix(got, `<svg><rect x="0" y="160" width="900" height="40" fill="#000" fill-opacity="0"><title>Needs &lt;budget&gt; &amp; approval</title></rect>`))
+		assert.Contains(t, got, `<rect x="880" y="0" width="40" height="200" fill="#000" fill-opacity="0"><title>First release</title></rect>`)
+		assert.True(t, strings.HasSuffix(got, "</svg>"))
+	})
+
+	t.Run("svg titles follow the drawing geometry", func(t *testing.T) {
+		fullW, lineH := 600.0, 30.0
+		fullH := vr.imageHeight(lineH)
+
+		got := string(vr.AddTooltips(img, SvgFormat, fullW, lineH))
+
+		y := fullH - rowTop(fullH, vr.headerHeight(lineH), lineH, 1)
+		x := vr.dateX(endAt, fullW) - lineH/2
+		assert.Contains(t, got, svgTooltip(0, y, fullW, lineH, "Needs <budget> & approval"))
+		assert.Contains(t, got, svgTooltip(x, 0, lineH, fullH, "First release"))
+	})
+}
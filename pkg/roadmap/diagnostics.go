@@ -0,0 +1,167 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity represents how serious a problem found during parsing is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic represents a problem found while parsing a Content
+// problems found in roadmaps without a text representation (e.g. stored ones) refer to the item affected by their
+// path (e.g. projects[2]) instead of a line and column
+type Diagnostic struct {
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Path     string   `json:"path,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Token    string   `json:"token,omitempty"`
+}
+
+// String converts a Diagnostic into a string
+func (d Diagnostic) String() string {
+	position := fmt.Sprintf("%d:%d", d.Line, d.Column)
+	if d.Path != "" {
+		position = d.Path
+	}
+
+	if d.Token == "" {
+		return fmt.Sprintf("%s: %s: %s", position, d.Severity, d.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s (%s)", position, d.Severity, d.Message, d.Token)
+}
+
+// projectPath returns the path of a project used by diagnostics (e.g. projects[2])
+func projectPath(i int) string {
+	return fmt.Sprintf("projects[%d]", i)
+}
+
+// milestonePath returns the path of a milestone used by diagnostics (e.g. milestones[0])
+func milestonePath(i int) string {
+	return fmt.Sprintf("milestones[%d]", i)
+}
+
+// locator positions diagnostics about projects and milestones, either on the lines they were read from, or by their
+// path if they were not read from text (e.g. received via the JSON API)
+type locator struct {
+	projectLines   []int
+	milestoneLines []int
+	byPath         bool
+}
+
+// project creates a diagnostic about a project
+func (l locator) project(i int, severity Severity, message, token string) Diagnostic {
+	if l.byPath {
+		return Diagnostic{Path: projectPath(i), Severity: severity, Message: message, Token: token}
+	}
+
+	return Diagnostic{Line: lineAt(l.projectLines, i), Column: 1, Severity: severity, Message: message, Token: token}
+}
+
+// milestone creates a diagnostic about a milestone
+func (l locator) milestone(i int, severity Severity, message, token string) Diagnostic {
+	if l.byPath {
+		return Diagnostic{Path: milestonePath(i), Severity: severity, Message: message, Token: token}
+	}
+
+	return Diagnostic{Line: lineAt(l.milestoneLines, i), Column: 1, Severity: severity, Message: message, Token: token}
+}
+
+// describeProject returns the position of a project to be used in messages (e.g. line 3)
+func (l locator) describeProject(i int) string {
+	if l.byPath {
+		return projectPath(i)
+	}
+
+	return fmt.Sprintf("line %d", lineAt(l.projectLines, i))
+}
+
+// describeMilestone returns the position of a milestone to be used in messages (e.g. line 7)
+func (l locator) describeMilestone(i int) string {
+	if l.byPath {
+		return milestonePath(i)
+	}
+
+	return fmt.Sprintf("line %d", lineAt(l.milestoneLines, i))
+}
+
+// lineAt returns the line of an item, 0 if it is not known
+func lineAt(lines []int, i int) int {
+	if i < len(lines) {
+		return lines[i]
+	}
+
+	return 0
+}
+
+// Diagnostics represents a list of problems found while parsing a Content
+type Diagnostics []Diagnostic
+
+// HasErrors returns true if at least one of the diagnostics is an error
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error returns the diagnostics as a string, making it possible to return them as an error
+func (ds Diagnostics) Error() string {
+	var lines []string
+
+	for _, d := range ds {
+		lines = append(lines, d.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sorted returns the diagnostics ordered by their position in the content
+func (ds Diagnostics) sorted() Diagnostics {
+	sort.SliceStable(ds, func(i, j int) bool {
+		if ds[i].Line != ds[j].Line {
+			return ds[i].Line < ds[j].Line
+		}
+
+		return ds[i].Column < ds[j].Column
+	})
+
+	return ds
+}
+
+var (
+	errEmptyExtra         = errors.New("empty value")
+	errUnknownExtra       = errors.New("unknown value, it will be ignored")
+	errEndDateOverwritten = errors.New("too many dates, the end date is overwritten")
+	errUnknownSetting     = errors.New("unknown setting, it will be ignored")
+)
+
+// newDiagnostic creates a Diagnostic from an error found while parsing
+// errors which only lead to losing some information are considered warnings, all others are errors
+func newDiagnostic(line, column int, err error, token string) Diagnostic {
+	severity := SeverityError
+	if errors.Is(err, errEmptyExtra) || errors.Is(err, errUnknownExtra) || errors.Is(err, errEndDateOverwritten) || errors.Is(err, errUnknownSetting) || errors.Is(err, errFieldOverwritten) {
+		severity = SeverityWarning
+	}
+
+	return Diagnostic{
+		Line:     line,
+		Column:   column,
+		Severity: severity,
+		Message:  err.Error(),
+		Token:    token,
+	}
+}
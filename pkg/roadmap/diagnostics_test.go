@@ -0,0 +1,81 @@
+package roadmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnostic_String(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Diagnostic
+		want string
+	}{
+		{
+			"without token",
+			Diagnostic{Line: 2, Column: 1, Severity: SeverityWarning, Message: "foo"},
+			"2:1: warning: foo",
+		},
+		{
+			"with token",
+			Diagnostic{Line: 3, Column: 12, Severity: SeverityError, Message: "invalid date", Token: "2020-02-31"},
+			"3:12: error: invalid date (2020-02-31)",
+		},
+		{
+			"with path",
+			Diagnostic{Path: "projects[2]", Severity: SeverityWarning, Message: "included roadmap not found", Token: "include:abc"},
+			"projects[2]: warning: included roadmap not found (include:abc)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.d.String())
+		})
+	}
+}
+
+func TestDiagnostics_HasErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		ds   Diagnostics
+		want bool
+	}{
+		{
+			"empty",
+			nil,
+			false,
+		},
+		{
+			"warnings only",
+			Diagnostics{{Severity: SeverityWarning}, {Severity: SeverityWarning}},
+			false,
+		},
+		{
+			"with error",
+			Diagnostics{{Severity: SeverityWarning}, {Severity: SeverityError}},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.ds.HasErrors())
+		})
+	}
+}
+
+func TestDiagnostics_sorted(t *testing.T) {
+	ds := Diagnostics{
+		{Line: 3, Column: 1},
+		{Line: 1, Column: 8},
+		{Line: 1, Column: 2},
+	}
+
+	want := Diagnostics{
+		{Line: 1, Column: 2},
+		{Line: 1, Column: 8},
+		{Line: 3, Column: 1},
+	}
+
+	assert.Equal(t, want, ds.sorted())
+}
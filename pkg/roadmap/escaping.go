@@ -0,0 +1,209 @@
+package roadmap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// escapeChar is used to escape characters which would otherwise be interpreted by the parser
+// (e.g. "Migrate \[legacy\] API", "https://example.com/?q=a\, b")
+const escapeChar = '\\'
+
+// escapable contains the characters which lose their special meaning when preceded by escapeChar
+// escapeChar followed by any other character is kept as is, so that existing content (e.g. "C:\temp") is not changed
+const escapable = `\[]|,/>`
+
+// controlEscapePrefix starts the escape sequence of a control character in a title (e.g. "\x0a" for a newline)
+// the sequence is only recognised for control characters, so that existing content (e.g. "C:\new", "C:\xbox") is not changed
+const controlEscapePrefix = `\x`
+
+// controlEscapeAt returns the control character represented by the escape sequence starting at position i of s
+func controlEscapeAt(s string, i int) (byte, bool) {
+	if !strings.HasPrefix(s[i:], controlEscapePrefix) || len(s) < i+len(controlEscapePrefix)+2 {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(s[i+len(controlEscapePrefix):i+len(controlEscapePrefix)+2], 16, 8)
+	if err != nil || !isControlChar(byte(n)) {
+		return 0, false
+	}
+
+	return byte(n), true
+}
+
+// isControlChar returns true for the characters which can not be written to a single line as is, tabs are allowed
+func isControlChar(c byte) bool {
+	return (c < ' ' && c != '\t') || c == 0x7f
+}
+
+// isEscaped returns true if the character at position i of s is preceded by an odd number of escape characters
+func isEscaped(s string, i int) bool {
+	n := 0
+
+	for j := i - 1; j >= 0 && s[j] == escapeChar; j-- {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// lastIndexUnescaped returns the index of the last unescaped occurrence of a character, -1 if not found
+func lastIndexUnescaped(s string, c byte) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == c && !isEscaped(s, i) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splitUnescaped splits a string by a separator, ignoring separators which start with an escaped character
+func splitUnescaped(s, sep string) []string {
+	var (
+		parts []string
+		start int
+	)
+
+	for i := 0; i+len(sep) <= len(s); i++ {
+		if s[i:i+len(sep)] != sep || isEscaped(s, i) {
+			continue
+		}
+
+		parts = append(parts, s[start:i])
+		start = i + len(sep)
+		i += len(sep) - 1
+	}
+
+	return append(parts, s[start:])
+}
+
+// unescape removes escape characters preceding escapable characters
+func unescape(s string) string {
+	if !strings.ContainsRune(s, escapeChar) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == escapeChar && i+1 < len(s) && strings.IndexByte(escapable, s[i+1]) >= 0 {
+			i++
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// unescapeTitle removes escape characters from a title, additionally to unescape it also restores control characters
+// (e.g. "First\x0aSecond") and leading characters which would otherwise be interpreted as indentation or a header delimiter
+func unescapeTitle(s string) string {
+	if !strings.ContainsRune(s, escapeChar) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != escapeChar || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		next := s[i+1]
+		c, isControl := controlEscapeAt(s, i)
+
+		switch {
+		case strings.IndexByte(escapable, next) >= 0:
+			i++
+		case isControl:
+			i += len(controlEscapePrefix) + 1
+			next = c
+		case i == 0 && (next == ' ' || next == '\t' || next == '-'):
+			i++
+		default:
+			next = s[i]
+		}
+
+		b.WriteByte(next)
+	}
+
+	return b.String()
+}
+
+// escapeTitle escapes the characters of a title of a project or milestone which would otherwise be interpreted as
+// the start of the extra part, a milestone, a description, a comment, indentation or the header, control characters
+// (e.g. newlines) are escaped so that the title stays on a single line, see unescapeTitle
+func escapeTitle(title string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(title); i++ {
+		c := title[i]
+
+		_, isControlEscape := controlEscapeAt(title, i)
+
+		switch {
+		case isControlChar(c):
+			fmt.Fprintf(&b, "%s%02x", controlEscapePrefix, c)
+			continue
+		case c == escapeChar && i+1 < len(title) && strings.IndexByte(escapable, title[i+1]) >= 0:
+			b.WriteByte(escapeChar)
+		case isControlEscape:
+			b.WriteByte(escapeChar)
+		case c == escapeChar && i+1 == len(title):
+			b.WriteByte(escapeChar)
+		case c == '[' || c == ']':
+			b.WriteByte(escapeChar)
+		case (c == '|' || c == '>' || c == ' ' || c == '\t') && i == 0:
+			b.WriteByte(escapeChar)
+		case c == '-' && i == 0 && strings.TrimSpace(title) == headerDelimiter:
+			b.WriteByte(escapeChar)
+		case isCommentStart(title, i):
+			b.WriteByte(escapeChar)
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// escapeExtra escapes the characters of a piece of extra information which would otherwise be interpreted as the end
+// of the extra part, a separator or a comment
+func escapeExtra(part string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(part); i++ {
+		c := part[i]
+
+		switch {
+		case c == escapeChar && i+1 < len(part) && strings.IndexByte(escapable, part[i+1]) >= 0:
+			b.WriteByte(escapeChar)
+		case c == escapeChar && i+1 == len(part):
+			b.WriteByte(escapeChar)
+		case c == '[' || c == ']':
+			b.WriteByte(escapeChar)
+		case c == ',' && i+1 < len(part) && part[i+1] == ' ':
+			b.WriteByte(escapeChar)
+		case isCommentStart(part, i):
+			b.WriteByte(escapeChar)
+		}
+
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// isCommentStart returns true if the character at position i of s would start a comment
+// pieces of extra information always follow whitespace, therefore a leading // would also start a comment
+func isCommentStart(s string, i int) bool {
+	if !strings.HasPrefix(s[i:], commentPrefix) {
+		return false
+	}
+
+	return i == 0 || s[i-1] == ' ' || s[i-1] == '\t'
+}
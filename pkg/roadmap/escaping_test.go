@@ -0,0 +1,195 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_unescape(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"nothing to unescape", "foo", "foo"},
+		{"brackets", `Migrate \[legacy\] API`, "Migrate [legacy] API"},
+		{"escaped escape character", `foo\\`, `foo\`},
+		{"non-escapable character", `C:\temp`, `C:\temp`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, unescape(tt.s))
+		})
+	}
+}
+
+func Test_unescapeTitle(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"nothing to unescape", "foo", "foo"},
+		{"newline", `foo\x0abar`, "foo\nbar"},
+		{"backslash n is kept", `Build C:\new folder`, `Build C:\new folder`},
+		{"backslash r is kept", `C:\reports`, `C:\reports`},
+		{"non-control character escape sequence is kept", `C:\x41`, `C:\x41`},
+		{"escaped control character escape sequence", `C:\\x0a`, `C:\x0a`},
+		{"incomplete escape sequence", `foo\x0`, `foo\x0`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, unescapeTitle(tt.s))
+		})
+	}
+}
+
+func Test_splitUnescaped(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+	}{
+		{"no separator", "foo", []string{"foo"}},
+		{"separators", "foo, bar, baz", []string{"foo", "bar", "baz"}},
+		{"escaped separator", `foo\, bar, baz`, []string{`foo\, bar`, "baz"}},
+		{"escaped escape character", `foo\\, bar`, []string{`foo\\`, "bar"}},
+		{"empty parts", ", ", []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitUnescaped(tt.s, ", "))
+		})
+	}
+}
+
+func Test_escapeTitle(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"nothing to escape", "foo bar", "foo bar"},
+		{"brackets", "Migrate [legacy] API", `Migrate \[legacy\] API`},
+		{"leading pipe", "|foo|", `\|foo|`},
+		{"leading description marker", "> foo", `\> foo`},
+		{"comment", "foo // bar", `foo \// bar`},
+		{"url is not a comment", "https://example.com/", "https://example.com/"},
+		{"escape character", `C:\temp\[x`, `C:\temp\\\[x`},
+		{"leading whitespace", "  foo", `\  foo`},
+		{"newlines", "foo\nbar\r\n", `foo\x0abar\x0d\x0a`},
+		{"escape character before n", `C:\new`, `C:\new`},
+		{"control character escape sequence", `C:\x0a`, `C:\\x0a`},
+		{"header delimiter", "---", `\---`},
+		{"dashes", "--- foo", "--- foo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeTitle(tt.title))
+		})
+	}
+}
+
+func TestRoadmap_String_escaping(t *testing.T) {
+	startAt := time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+	endAt := time.Date(2020, 2, 20, 0, 0, 0, 0, time.UTC)
+
+	r := Roadmap{
+		DateFormat: "2006-01-02",
+		BaseURL:    "https://example.com/",
+		Projects: []Project{
+			{Title: "Migrate [legacy] API", Dates: &Dates{StartAt: startAt, EndAt: endAt}, URLs: []string{"https://example.com/?q=a, b[]"}},
+			{Title: "|Pipes| and // slashes", Indentation: 1, ID: "a, b", Comment: "real comment"},
+			{Title: `Back\slashes \[ and \\`, Indentation: 1, DependsOn: []string{"a, b", "Migrate [legacy] API"}},
+			{Title: "//", URLs: []string{"//example.com/foo"}},
+			{Title: "  Leading whitespace", Indentation: 1},
+			{Title: "\tLeading tab"},
+			{Title: "Multi\nline\r\ntitle", Indentation: 1},
+			{Title: `C:\new`, Indentation: 1},
+			{Title: `C:\x0d`, Indentation: 1},
+		},
+		Milestones: []Milestone{
+			{Title: "|Milestone [1]", URLs: []string{"https://example.com/a, b"}},
+		},
+	}
+
+	got, ds := r.ToContent().Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Equal(t, r.Projects, got.Projects)
+	assert.Equal(t, r.Milestones, got.Milestones)
+}
+
+func TestRoadmap_String_escapingHeaderDelimiter(t *testing.T) {
+	r := Roadmap{
+		DateFormat: "2006-01-02",
+		Projects: []Project{
+			{Title: "---"},
+			{Title: "foo"},
+			{Title: "---", Indentation: 1},
+		},
+	}
+
+	got, ds := r.ToContent().Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Equal(t, r.Projects, got.Projects)
+}
+
+func TestRoadmap_String_numericMilestoneRef(t *testing.T) {
+	r := Roadmap{
+		DateFormat: "2006-01-02",
+		Projects: []Project{
+			{Title: "Backend", MilestoneRef: "2"},
+			{Title: "Frontend", MilestoneRef: "1"},
+		},
+		Milestones: []Milestone{
+			{Title: "2"},
+			{Title: "1"},
+		},
+	}
+
+	got, ds := r.ToContent().Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	for i, p := range r.Projects {
+		assert.Equal(t, findMilestone(r.Milestones, p), findMilestone(got.Milestones, got.Projects[i]))
+	}
+
+	got, ds = ParseCSV(r.ToCSV(), 0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	for i, p := range r.Projects {
+		assert.Equal(t, findMilestone(r.Milestones, p), findMilestone(got.Milestones, got.Projects[i]))
+	}
+}
+
+func TestContent_Parse_crlf(t *testing.T) {
+	r := Roadmap{
+		DateFormat: "2006-01-02",
+		Projects: []Project{
+			{Title: "Epic"},
+			{Title: "Story [1]", Indentation: 1, Dates: &Dates{StartAt: time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 20, 0, 0, 0, 0, time.UTC)}},
+		},
+		Milestones: []Milestone{
+			{Title: "Release"},
+		},
+	}
+
+	content := r.ToContent()
+
+	for i := 0; i < 3; i++ {
+		crlf := Content(strings.ReplaceAll(string(content), "\n", "\r\n"))
+
+		got, ds := crlf.Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+		assert.Empty(t, ds)
+		assert.Equal(t, r.Projects, got.Projects)
+		assert.Equal(t, r.Milestones, got.Milestones)
+
+		content = got.ToContent()
+	}
+}
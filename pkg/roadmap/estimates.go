@@ -0,0 +1,87 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// estimatePrefix starts the effort estimate of a project, e.g. in story points (e.g. estimate=8)
+const estimatePrefix = "estimate="
+
+var (
+	errCannotParseEstimate = errors.New("can not parse string as estimate, expected a positive number (e.g. estimate=8)")
+	errReservedEstimateKey = errors.New("estimate is reserved for the estimate of projects and can not be used as a field key, expected a positive number (e.g. estimate=8)")
+)
+
+// parseEstimate tries to parse a string as the estimate of a project (e.g. estimate=8, estimate=0.5)
+// estimate can not be used as the key of a custom field, non-numeric values result in an error explaining this
+func parseEstimate(part string) (float64, error) {
+	e, err := strconv.ParseFloat(part[len(estimatePrefix):], 64)
+	if err != nil {
+		return 0, errReservedEstimateKey
+	}
+
+	if e <= 0 || math.IsInf(e, 0) || math.IsNaN(e) {
+		return 0, errCannotParseEstimate
+	}
+
+	return e, nil
+}
+
+// formatEstimate converts an estimate into a string usable in the extra part of a project
+func formatEstimate(e float64) string {
+	return estimatePrefix + strconv.FormatFloat(e, 'f', -1, 64)
+}
+
+// rollUpEstimates calculates the total and remaining estimates of all projects bottom up
+// projects with an estimate use their own estimate and percentage, all others sum up their sub-projects
+func rollUpEstimates(projects []Project) {
+	for i := len(projects) - 1; i >= 0; i-- {
+		p := &projects[i]
+
+		if p.Estimate > 0 {
+			p.EstimateTotal = p.Estimate
+			p.EstimateRemaining = p.Estimate * float64(100-p.Percentage) / 100
+
+			continue
+		}
+
+		p.EstimateTotal, p.EstimateRemaining = 0, 0
+
+		// sub-projects are already calculated as the loop goes backwards
+		for j := i + 1; j < len(projects) && projects[j].Indentation > p.Indentation; j++ {
+			if projects[j].Indentation != p.Indentation+1 {
+				continue
+			}
+
+			p.EstimateTotal += projects[j].EstimateTotal
+			p.EstimateRemaining += projects[j].EstimateRemaining
+		}
+	}
+}
+
+// calculateEstimates calculates the total and remaining estimates of all projects, see rollUpEstimates
+func (vr *VisualRoadmap) calculateEstimates() *VisualRoadmap {
+	rollUpEstimates(vr.Projects)
+
+	return vr
+}
+
+// estimateLabel creates the label showing the remaining and total estimates of a parent project (e.g. "5 / 13")
+// projects without sub-projects or without estimates have no label
+func (vr *VisualRoadmap) estimateLabel(i int) string {
+	p := vr.Projects[i]
+
+	if p.EstimateTotal == 0 || i+1 >= len(vr.Projects) || vr.Projects[i+1].Indentation <= p.Indentation {
+		return ""
+	}
+
+	return fmt.Sprintf("%s / %s", formatAmount(p.EstimateRemaining), formatAmount(p.EstimateTotal))
+}
+
+// formatAmount formats an amount of effort with at most one decimal
+func formatAmount(a float64) string {
+	return strconv.FormatFloat(math.Round(a*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,109 @@
+package roadmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseEstimate(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    float64
+		wantErr error
+	}{
+		{"integer", "estimate=8", 8, nil},
+		{"fraction", "estimate=0.5", 0.5, nil},
+		{"negative", "estimate=-3", 0, errCannotParseEstimate},
+		{"not a number", "estimate=NaN", 0, errCannotParseEstimate},
+		{"infinite", "estimate=+Inf", 0, errCannotParseEstimate},
+		{"reserved key", "estimate=large", 0, errReservedEstimateKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEstimate(tt.part)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_rollUpEstimates(t *testing.T) {
+	projects := []Project{
+		{Title: "Epic"},
+		{Title: "Story 1", Indentation: 1, Estimate: 5, Percentage: 100},
+		{Title: "Story 2", Indentation: 1},
+		{Title: "Task 2.1", Indentation: 2, Estimate: 3, Percentage: 50},
+		{Title: "Task 2.2", Indentation: 2, Estimate: 5},
+		{Title: "Story 3", Indentation: 1, Estimate: 2},
+		{Title: "Task 3.1", Indentation: 2, Estimate: 40},
+		{Title: "Other epic"},
+	}
+
+	rollUpEstimates(projects)
+
+	type totals struct{ total, remaining float64 }
+
+	var got []totals
+	for _, p := range projects {
+		got = append(got, totals{p.EstimateTotal, p.EstimateRemaining})
+	}
+
+	assert.Equal(t, []totals{{15, 8.5}, {5, 0}, {8, 6.5}, {3, 1.5}, {5, 5}, {2, 2}, {40, 40}, {0, 0}}, got)
+}
+
+func TestVisualRoadmap_sideLabel(t *testing.T) {
+	vr := &VisualRoadmap{
+		Projects: []Project{
+			{Title: "Epic", Owners: []string{"peter"}},
+			{Title: "Story 1", Indentation: 1, Estimate: 5, Percentage: 100, Owners: []string{"anna"}},
+			{Title: "Story 2", Indentation: 1, Estimate: 8},
+		},
+	}
+
+	vr.calculateEstimates()
+
+	assert.Equal(t, "8 / 13  @peter", vr.sideLabel(0))
+	assert.Equal(t, "@anna", vr.sideLabel(1))
+	assert.Equal(t, "", vr.sideLabel(2))
+}
+
+func TestRoadmap_ToExchange_estimates(t *testing.T) {
+	r := Roadmap{
+		Projects: []Project{
+			{Title: "Epic"},
+			{Title: "Story 1", Indentation: 1, Estimate: 5, Percentage: 40},
+		},
+	}
+
+	got := r.ToExchange()
+
+	assert.Equal(t, 5.0, got.Projects[0].EstimateTotal)
+	assert.Equal(t, 3.0, got.Projects[0].EstimateRemaining)
+	assert.Equal(t, 0.0, r.Projects[0].EstimateTotal)
+	assert.Equal(t, "Epic\n\tStory 1 [40%, estimate=5]", r.String())
+}
+
+func TestRoadmapExchange_ToRoadmap_estimates(t *testing.T) {
+	re := RoadmapExchange{
+		Projects: []Project{
+			{Title: "Epic", EstimateTotal: 100, EstimateRemaining: 50},
+			{Title: "Story 1", Indentation: 1, Estimate: 5, Percentage: 40, EstimateTotal: 8, EstimateRemaining: 8},
+		},
+	}
+
+	r := re.ToRoadmap()
+
+	for _, p := range r.Projects {
+		assert.Equal(t, 0.0, p.EstimateTotal)
+		assert.Equal(t, 0.0, p.EstimateRemaining)
+	}
+	assert.Equal(t, 100.0, re.Projects[0].EstimateTotal)
+
+	got := r.ToExchange()
+
+	assert.Equal(t, 5.0, got.Projects[0].EstimateTotal)
+	assert.Equal(t, 3.0, got.Projects[0].EstimateRemaining)
+	assert.Equal(t, 5.0, got.Projects[1].EstimateTotal)
+}
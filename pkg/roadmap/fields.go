@@ -0,0 +1,185 @@
+package roadmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fieldSeparator separates the key and the value of a custom field (e.g. team=payments)
+const fieldSeparator = "="
+
+// fieldRegexp matches custom fields, keys must start with a letter and may contain letters, digits, dots, dashes
+// and underscores
+var fieldRegexp = regexp.MustCompile(`^([A-Za-z][\w.-]*)=(.*)$`)
+
+var (
+	errCannotParseField = errors.New("can not parse string as field, expected format: key=value")
+	errFieldOverwritten = errors.New("field is already set, its value is overwritten")
+)
+
+// Field represents a custom piece of information stored on a project or milestone (e.g. team=payments)
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Fields represents custom fields in the order they were defined
+// they are represented as a JSON object, keeping the order of keys
+type Fields []Field
+
+// isField returns true if a string looks like a custom field (e.g. team=payments)
+func isField(part string) bool {
+	return fieldRegexp.MatchString(part)
+}
+
+// parseField tries to parse a string as a custom field (e.g. team=payments)
+func parseField(part string) (Field, error) {
+	m := fieldRegexp.FindStringSubmatch(part)
+	if m == nil || strings.TrimSpace(m[2]) == "" {
+		return Field{}, errCannotParseField
+	}
+
+	return Field{Key: m[1], Value: m[2]}, nil
+}
+
+// Get returns the value of a field and whether it was found
+func (fs Fields) Get(key string) (string, bool) {
+	for _, f := range fs {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// set adds a field or overwrites the value of an existing one, keeping its position
+// the boolean returned is true if an existing field was overwritten
+func (fs Fields) set(f Field) (Fields, bool) {
+	for i := range fs {
+		if fs[i].Key == f.Key {
+			fs[i].Value = f.Value
+
+			return fs, true
+		}
+	}
+
+	return append(fs, f), false
+}
+
+// String converts a Field into a string
+func (f Field) String() string {
+	return f.Key + fieldSeparator + f.Value
+}
+
+// MarshalJSON converts Fields into a JSON object, keeping the order of keys
+func (fs Fields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, f := range fs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON converts a JSON object into Fields, keeping the order of keys
+func (fs *Fields) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if t == nil {
+		*fs = nil
+
+		return nil
+	}
+
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("fields must be an object, got: %v", t)
+	}
+
+	var result Fields
+
+	for dec.More() {
+		t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var v string
+		err = dec.Decode(&v)
+		if err != nil {
+			return fmt.Errorf("value of field %v must be a string: %w", t, err)
+		}
+
+		result, _ = result.set(Field{Key: t.(string), Value: v})
+	}
+
+	*fs = result
+
+	return nil
+}
+
+// templatePlaceholderRegexp matches the placeholders of title templates (e.g. {title}, {team})
+var templatePlaceholderRegexp = regexp.MustCompile(`\{([A-Za-z][\w.-]*)\}`)
+
+// templateTitle is the placeholder of title templates replaced by the original title
+const templateTitle = "title"
+
+// expandTitleTemplate creates the title displayed for a project or milestone based on a template
+// {title} is replaced by the original title, any other placeholder by the value of the custom field of the same key,
+// placeholders of missing fields are removed
+func expandTitleTemplate(tmpl, title string, fields Fields) string {
+	if tmpl == "" {
+		return title
+	}
+
+	result := templatePlaceholderRegexp.ReplaceAllStringFunc(tmpl, func(placeholder string) string {
+		key := placeholder[1 : len(placeholder)-1]
+		if key == templateTitle {
+			return title
+		}
+
+		v, _ := fields.Get(key)
+
+		return v
+	})
+
+	return strings.TrimSpace(result)
+}
+
+// ApplyTitleTemplate sets the template used for displaying the titles of projects and milestones
+// (e.g. "{title} ({team})")
+func (vr *VisualRoadmap) ApplyTitleTemplate(tmpl string) *VisualRoadmap {
+	vr.TitleTemplate = tmpl
+
+	return vr
+}
@@ -0,0 +1,71 @@
+package roadmap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseField(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    Field
+		wantErr error
+	}{
+		{"field", "team=payments", Field{Key: "team", Value: "payments"}, nil},
+		{"value with equal sign", "query=a=b", Field{Key: "query", Value: "a=b"}, nil},
+		{"missing value", "team=", Field{}, errCannotParseField},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseField(tt.part)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_isField(t *testing.T) {
+	assert.True(t, isField("cost=40k"))
+	assert.False(t, isField("=40k"))
+	assert.False(t, isField("https://example.com/?a=b"))
+	assert.False(t, isField("page?a=b"))
+}
+
+func TestFields_JSON(t *testing.T) {
+	fs := Fields{{Key: "team", Value: "payments"}, {Key: "cost", Value: "40k"}, {Key: "area", Value: "\"core\""}}
+
+	data, err := json.Marshal(fs)
+	require.NoError(t, err)
+	assert.Equal(t, `{"team":"payments","cost":"40k","area":"\"core\""}`, string(data))
+
+	var got Fields
+	err = json.Unmarshal(data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, fs, got)
+
+	err = json.Unmarshal([]byte(`["team"]`), &got)
+	assert.Error(t, err)
+}
+
+func Test_expandTitleTemplate(t *testing.T) {
+	fields := Fields{{Key: "team", Value: "payments"}, {Key: "cost", Value: "40k"}}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"no template", "", "Create API"},
+		{"fields", "{title} ({team}, {cost})", "Create API (payments, 40k)"},
+		{"missing field", "{title} {owner}", "Create API"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, expandTitleTemplate(tt.tmpl, "Create API", fields))
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"time"
@@ -58,7 +59,7 @@ func (h *Handler) GetRoadmapJSON(ctx echo.Context) error {
 		return ctx.JSON(status, p)
 	}
 
-	r, err := load(h.repo, h.cb, identifier)
+	r, ds, err := h.loadResolved(identifier)
 	if err != nil || r == nil {
 		status := herr.ToHttpCode(err, http.StatusInternalServerError)
 		err = fmt.Errorf("unable to load roadmap: %w", err)
@@ -74,10 +75,17 @@ func (h *Handler) GetRoadmapJSON(ctx echo.Context) error {
 	}
 
 	re := r.ToExchange()
+	re.Diagnostics = ds
 
 	return ctx.JSON(http.StatusOK, re)
 }
 
+// diagnosticsProblem represents a problem caused by errors found in a roadmap
+type diagnosticsProblem struct {
+	problem.Problem
+	Diagnostics Diagnostics `json:"diagnostics,omitempty"`
+}
+
 func (h *Handler) CreateRoadmapJSON(ctx echo.Context) error {
 	re := RoadmapExchange{}
 	err := json.NewDecoder(ctx.Request().Body).Decode(&re)
@@ -98,7 +106,65 @@ func (h *Handler) CreateRoadmapJSON(ctx echo.Context) error {
 	r := re.ToRoadmap()
 	r.ID = code.NewCode64().ID()
 
-	err = h.isValidRoadmap(r)
+	var (
+		detection DateFormatDetection
+		detected  = r.DateFormat == ""
+	)
+	if detected {
+		detection = r.detectDateFormat()
+		r.DateFormat = detection.formatOrDefault()
+	}
+
+	var ds Diagnostics
+	for _, d := range detection.diagnostics(r.DateFormat, detected) {
+		d.Path = "date_format"
+		ds = append(ds, d)
+	}
+
+	return h.createRoadmap(ctx, r, append(ds, r.Validate()...))
+}
+
+// CreateRoadmapMermaid creates a roadmap from a Mermaid gantt chart sent as the request body
+func (h *Handler) CreateRoadmapMermaid(ctx echo.Context) error {
+	body, err := ioutil.ReadAll(ctx.Request().Body)
+	if err != nil {
+		err = fmt.Errorf("unable to read the given roadmap: %w", err)
+		h.Logger.Error("failed creating request", zap.Error(err))
+		status := herr.ToHttpCode(err, http.StatusBadRequest)
+
+		p := problem.Problem{
+			Type:   "https://docs.rdmp.app/problem/roadmap-payload-parsing-error",
+			Title:  "Roadmap Payload Parsing Error",
+			Status: status,
+		}
+
+		return ctx.JSON(status, p)
+	}
+
+	r, ds := ParseMermaid(string(body), code.NewCode64().ID(), nil, ctx.QueryParam("title"), ctx.QueryParam("baseUrl"), time.Now())
+	if ds.HasErrors() {
+		h.Logger.Error("failed creating request", zap.Error(fmt.Errorf("roadmap diagnostics: %w", ds)))
+		status := http.StatusBadRequest
+
+		p := diagnosticsProblem{
+			Problem: problem.Problem{
+				Type:   "https://docs.rdmp.app/problem/roadmap-payload-parsing-error",
+				Title:  "Roadmap Payload Parsing Error",
+				Status: status,
+			},
+			Diagnostics: ds,
+		}
+
+		return ctx.JSON(status, p)
+	}
+
+	return h.createRoadmap(ctx, r, ds)
+}
+
+// createRoadmap stores a roadmap received via the API unless the diagnostics found while reading it contain errors,
+// the diagnostics are included in the response
+func (h *Handler) createRoadmap(ctx echo.Context, r Roadmap, ds Diagnostics) error {
+	err := h.isValidRoadmap(r)
 	if err != nil {
 		err = fmt.Errorf("roadmap validation error: %w", err)
 		h.Logger.Error("failed creating request", zap.Error(err))
@@ -113,6 +179,22 @@ func (h *Handler) CreateRoadmapJSON(ctx echo.Context) error {
 		return ctx.JSON(status, p)
 	}
 
+	if ds.HasErrors() {
+		h.Logger.Error("failed creating request", zap.Error(fmt.Errorf("roadmap diagnostics: %w", ds)))
+		status := http.StatusBadRequest
+
+		p := diagnosticsProblem{
+			Problem: problem.Problem{
+				Type:   "https://docs.rdmp.app/problem/roadmap-payload-validation-error",
+				Title:  "Roadmap Payload Validation Error",
+				Status: status,
+			},
+			Diagnostics: ds,
+		}
+
+		return ctx.JSON(status, p)
+	}
+
 	err = h.repo.Create(r)
 	if err != nil {
 		err = fmt.Errorf("failed to write the new roadmap: %w", err)
@@ -128,7 +210,8 @@ func (h *Handler) CreateRoadmapJSON(ctx echo.Context) error {
 		return ctx.JSON(status, p)
 	}
 
-	re = r.ToExchange()
+	re := r.ToExchange()
+	re.Diagnostics = ds
 
 	return ctx.JSON(http.StatusCreated, re)
 }
@@ -136,16 +219,27 @@ func (h *Handler) CreateRoadmapJSON(ctx echo.Context) error {
 func (h *Handler) GetRoadmapHTML(ctx echo.Context) error {
 	identifier := ctx.Param("identifier")
 
-	r, err := load(h.repo, h.cb, identifier)
+	r, ds, err := h.loadResolved(identifier)
 	if err != nil {
 		return h.displayHTML(ctx, r, err)
 	}
 
-	return h.displayHTML(ctx, r, nil)
+	return h.displayHTMLWithDiagnostics(ctx, r, "", ds, nil)
 }
 
 func (h *Handler) displayHTML(ctx echo.Context, r *Roadmap, origErr error) error {
-	output, err := r.viewHtml(h.appVersion, h.matomoDomain, h.docBaseURL, ctx.Request().RequestURI, h.selfHosted, origErr)
+	return h.displayHTMLWithDiagnostics(ctx, r, "", nil, origErr)
+}
+
+// displayHTMLWithDiagnostics displays a roadmap together with the problems found in it, if the roadmap could not be
+// saved, the raw content submitted is displayed as well
+func (h *Handler) displayHTMLWithDiagnostics(ctx echo.Context, r *Roadmap, raw string, ds Diagnostics, origErr error) error {
+	return h.displayHTMLAt(ctx, ctx.Request().RequestURI, r, raw, ds, origErr)
+}
+
+// displayHTMLAt displays a roadmap as if it was requested from the URL given
+func (h *Handler) displayHTMLAt(ctx echo.Context, currentURL string, r *Roadmap, raw string, ds Diagnostics, origErr error) error {
+	output, err := r.viewHtml(h.appVersion, h.matomoDomain, h.docBaseURL, currentURL, h.selfHosted, raw, ds, origErr)
 	if origErr == nil && err == nil {
 		return ctx.HTML(http.StatusOK, output)
 	}
@@ -187,7 +281,26 @@ func (h *Handler) CreateRoadmapHTML(ctx echo.Context) error {
 	baseURL := ctx.FormValue("baseUrl")
 	now := time.Now()
 
-	roadmap := Content(content).ToRoadmap(code.NewCode64().ID(), prevID, title, dateFormat, baseURL, now)
+	var (
+		roadmap Roadmap
+		ds      Diagnostics
+	)
+
+	switch InputFormat(ctx.FormValue("inputFormat")) {
+	case MermaidInputFormat:
+		roadmap, ds = ParseMermaid(content, code.NewCode64().ID(), prevID, title, baseURL, now)
+	case CSVFormat:
+		roadmap, ds = ParseCSV(content, code.NewCode64().ID(), prevID, title, dateFormat, baseURL, now)
+	default:
+		roadmap, ds = Content(content).Parse(code.NewCode64().ID(), prevID, title, dateFormat, baseURL, now)
+	}
+	if ds.HasErrors() {
+		h.Logger.Info("roadmap contains errors", zap.Error(ds))
+
+		err = herr.NewFromString("the roadmap contains errors, please fix them", http.StatusBadRequest)
+
+		return h.displayHTMLWithDiagnostics(ctx, &roadmap, content, ds, err)
+	}
 
 	err = h.isValidRoadmap(roadmap)
 	if err != nil {
@@ -207,6 +320,11 @@ func (h *Handler) CreateRoadmapHTML(ctx echo.Context) error {
 
 	newURL := fmt.Sprintf("/%s", c.String())
 
+	// warnings would be lost by redirecting, therefore the new roadmap is displayed right away
+	if len(ds) > 0 {
+		return h.displayHTMLAt(ctx, newURL, &roadmap, "", ds, nil)
+	}
+
 	return ctx.Redirect(http.StatusSeeOther, newURL)
 }
 
@@ -274,18 +392,52 @@ func (h *Handler) GetRoadmapImage(ctx echo.Context) error {
 
 	mt, _ := strconv.ParseBool(ctx.QueryParam("markToday"))
 
+	referenceDate := ctx.QueryParam("referenceDate")
+
+	layout, err := NewLayout(ctx.QueryParam("layout"))
+	if err != nil {
+		h.Logger.Info("layout is not supported", zap.Error(err))
+
+		return ctx.String(herr.ToHttpCode(err, http.StatusBadRequest), "layout is not supported")
+	}
+
+	tz, err := ParseTimezone(ctx.QueryParam("timezone"))
+	if err != nil {
+		h.Logger.Info("timezone is not supported", zap.Error(err))
+
+		return ctx.String(herr.ToHttpCode(err, http.StatusBadRequest), "timezone is not supported")
+	}
+
 	fw, lh = GetCanvasSizes(fw, lh)
 
-	r, err := load(h.repo, h.cb, ctx.Param("identifier"))
+	r, _, err := h.loadResolved(ctx.Param("identifier"))
 	if err != nil {
 		h.Logger.Info("roadmap not found", zap.Error(err))
 
 		return ctx.String(herr.ToHttpCode(err, http.StatusNotFound), "roadmap not found")
 	}
 
-	cvs := r.ToVisual().Draw(float64(fw), float64(lh), mt)
+	referenceAt, err := ParseReferenceDate(referenceDate, r.DateFormat)
+	if err != nil {
+		h.Logger.Info("reference date is not valid", zap.Error(err))
+
+		return ctx.String(herr.ToHttpCode(err, http.StatusBadRequest), "reference date is not valid")
+	}
+
+	include := ParseLabelList(ctx.QueryParam("include"))
+
+	exclude := ParseLabelList(ctx.QueryParam("exclude"))
+
+	calendarProjects, _ := strconv.ParseBool(ctx.QueryParam("projects"))
+
+	vr := r.ToVisual().FilterByLabels(include, exclude).ApplyLayout(layout).ApplyTitleTemplate(ctx.QueryParam("titleTemplate")).ApplyTimezone(tz).ApplyCalendarProjects(calendarProjects)
+
+	// finding the first version of a roadmap may take many reads, so it's only done when it's needed
+	if format == IcsFormat {
+		vr = vr.ApplyCalendarCode(h.rootCode(r))
+	}
 
-	img := RenderImg(cvs, format)
+	img := vr.Render(format, float64(fw), float64(lh), mt || referenceDate != "", referenceAt)
 
 	setHeaderContentType(ctx.Response().Header(), format)
 
@@ -294,7 +446,74 @@ func (h *Handler) GetRoadmapImage(ctx echo.Context) error {
 	return err
 }
 
+// GetRoadmapCSV returns a roadmap in CSV format as a download
+// included projects are marked in the CSV, so that they are skipped when it is imported again, see ParseCSV
+func (h *Handler) GetRoadmapCSV(ctx echo.Context) error {
+	identifier := ctx.Param("identifier")
+
+	r, _, err := h.loadResolved(identifier)
+	if err != nil {
+		h.Logger.Info("roadmap not found", zap.Error(err))
+
+		return ctx.String(herr.ToHttpCode(err, http.StatusNotFound), "roadmap not found")
+	}
+
+	if r == nil {
+		return ctx.String(http.StatusNotFound, "roadmap not found")
+	}
+
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.csv"`, identifier))
+
+	return ctx.Blob(http.StatusOK, "text/csv; charset=UTF-8", []byte(r.ToCSV()))
+}
+
+// maxVersions limits the number of previous versions followed when looking for the first version of a roadmap
+const maxVersions = 100
+
+// rootCode returns the code of the first version of a roadmap, which stays the same across new versions of a roadmap
+func (h *Handler) rootCode(r *Roadmap) string {
+	root := r
+	for i := 0; i < maxVersions && root.PrevID != nil && *root.PrevID != root.ID; i++ {
+		c, err := h.cb.NewFromID(*root.PrevID)
+		if err != nil {
+			break
+		}
+
+		prev, err := h.repo.Get(c)
+		if err != nil || prev == nil {
+			break
+		}
+
+		root = prev
+	}
+
+	return code.Uint64ToString(root.ID)
+}
+
+// loadResolved loads a roadmap and resolves its includes, problems with includes are returned as diagnostics
+// all endpoints displaying or exporting stored roadmaps are expected to load them this way
+func (h *Handler) loadResolved(identifier string) (*Roadmap, Diagnostics, error) {
+	r, err := load(h.repo, h.cb, identifier)
+	if err != nil || r == nil {
+		return r, nil, err
+	}
+
+	resolved, ds := r.resolveIncludes(func(identifier string) (*Roadmap, error) {
+		return loadBy(h.repo.GetLatest, h.cb, identifier)
+	})
+	if len(ds) > 0 {
+		h.Logger.Info("included roadmaps could not be resolved", zap.Error(ds))
+	}
+
+	return &resolved, ds, nil
+}
+
 func load(rw DbReadWriter, b code.Builder, identifier string) (*Roadmap, error) {
+	return loadBy(rw.Get, b, identifier)
+}
+
+// loadBy loads a roadmap by its identifier using the repository method given (e.g. DbReadWriter.GetLatest)
+func loadBy(get func(c code.Code) (*Roadmap, error), b code.Builder, identifier string) (*Roadmap, error) {
 	if identifier == "" {
 		return nil, nil
 	}
@@ -304,7 +523,7 @@ func load(rw DbReadWriter, b code.Builder, identifier string) (*Roadmap, error)
 		return nil, herr.NewFromError(err, http.StatusBadRequest)
 	}
 
-	roadmap, err := rw.Get(c)
+	roadmap, err := get(c)
 	if err != nil {
 		return nil, herr.NewFromError(err, http.StatusInternalServerError)
 	}
@@ -320,8 +539,10 @@ const (
 type FileFormat string
 
 const (
-	SvgFormat FileFormat = "svg"
-	PngFormat FileFormat = "png"
+	SvgFormat     FileFormat = "svg"
+	PngFormat     FileFormat = "png"
+	MermaidFormat FileFormat = "mermaid"
+	IcsFormat     FileFormat = "ics"
 )
 
 func NewFormatType(t string) (FileFormat, error) {
@@ -330,6 +551,10 @@ func NewFormatType(t string) (FileFormat, error) {
 		return SvgFormat, nil
 	case "png":
 		return PngFormat, nil
+	case "mermaid":
+		return MermaidFormat, nil
+	case "ics":
+		return IcsFormat, nil
 	}
 
 	return "", fmt.Errorf("unsupported image format: %s", t)
@@ -341,7 +566,25 @@ func setHeaderContentType(header http.Header, fileFormat FileFormat) {
 		header.Set(echo.HeaderContentType, "image/svg+xml")
 	case PngFormat:
 		header.Set(echo.HeaderContentType, "image/png")
+	case MermaidFormat:
+		header.Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+	case IcsFormat:
+		header.Set(echo.HeaderContentType, "text/calendar; charset=UTF-8")
+	}
+}
+
+// Render converts a visual roadmap into the format given, images are drawn using the sizes given
+func (vr *VisualRoadmap) Render(format FileFormat, fullW, lineH float64, withToday bool, referenceAt time.Time) []byte {
+	switch format {
+	case MermaidFormat:
+		return []byte(vr.ToMermaid())
+	case IcsFormat:
+		return []byte(vr.ToICS(time.Now()))
 	}
+
+	cvs := vr.Draw(fullW, lineH, withToday, referenceAt)
+
+	return vr.AddTooltips(RenderImg(cvs, format), format, fullW, lineH)
 }
 
 func RenderImg(cvs *canvas.Canvas, fileFormat FileFormat) []byte {
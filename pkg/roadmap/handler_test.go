@@ -143,6 +143,104 @@ func Test_handler_getRoadmapJSON(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), rdmp.Title)
 		drwMock.AssertExpectations(t)
 	})
+
+	t.Run("success - includes are resolved, missing ones are reported", func(t *testing.T) {
+		// Setup
+		var teamID, missingID uint64 = 8, 9
+		rdmp := createStubRoadmap()
+		rdmp.Projects = append(rdmp.Projects, Project{Title: "Team", Include: code.Uint64ToString(teamID)}, Project{Title: "Gone", Include: code.Uint64ToString(missingID)})
+		team := &Roadmap{ID: teamID, Projects: []Project{{Title: "Team project"}}}
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/abc", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/api/:identifier")
+		ctx.SetParamNames("identifier")
+		ctx.SetParamValues(code.Uint64ToString(rdmp.ID))
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", code.Code64(rdmp.ID)).
+			Return(rdmp, nil)
+		drwMock.
+			On("GetLatest", code.Code64(teamID)).
+			Return(team, nil)
+		drwMock.
+			On("GetLatest", code.Code64(missingID)).
+			Return(nil, nil)
+
+		// Run
+		err := h.GetRoadmapJSON(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var got RoadmapExchange
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+
+		n := len(rdmp.Projects)
+		require.Len(t, got.Projects, n+1)
+		assert.Equal(t, "Team project", got.Projects[n-1].Title)
+		assert.Equal(t, code.Uint64ToString(teamID), got.Projects[n-1].IncludedFrom)
+		assert.Equal(t, Diagnostics{{Path: projectPath(n - 1), Severity: SeverityWarning, Message: "included roadmap not found: " + code.Uint64ToString(missingID), Token: includePrefix + code.Uint64ToString(missingID)}}, got.Diagnostics)
+
+		// posting the roadmap back does not duplicate included projects
+		assert.Equal(t, rdmp.Projects, got.ToRoadmap().Projects)
+		drwMock.AssertExpectations(t)
+	})
+}
+
+func Test_handler_getRoadmapCSV(t *testing.T) {
+	t.Run("fail - not found", func(t *testing.T) {
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/abc/csv", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/csv")
+		ctx.SetParamNames("identifier")
+		ctx.SetParamValues("abc")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", mock.AnythingOfType("code.Code64")).
+			Return(nil, nil)
+
+		// Run
+		err := h.GetRoadmapCSV(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// Setup
+		rdmp := createStubRoadmap()
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/abc/csv", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/csv")
+		ctx.SetParamNames("identifier")
+		ctx.SetParamValues("abc")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", mock.AnythingOfType("code.Code64")).
+			Return(rdmp, nil)
+
+		// Run
+		err := h.GetRoadmapCSV(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `attachment; filename="abc.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, rdmp.ToCSV(), rec.Body.String())
+		drwMock.AssertExpectations(t)
+	})
 }
 
 func Test_handler_createRoadmapJSON(t *testing.T) {
@@ -197,6 +295,35 @@ func Test_handler_createRoadmapJSON(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), "Roadmap Payload Validation Error")
 	})
 
+	t.Run("fail - roadmap diagnostics", func(t *testing.T) {
+		// Setup
+		rdmp := createStubRoadmap()
+		rdmp.Projects[0].Percentage = 120
+		jsonBody, err := json.Marshal(rdmp.ToExchange())
+		require.NoError(t, err)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/abc", bytes.NewReader(jsonBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextHTML)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/api/:identifier")
+		ctx.SetParamNames("identifier")
+		ctx.SetParamValues("abc")
+
+		h, _ := setupHandler()
+
+		// Run
+		err = h.CreateRoadmapJSON(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Roadmap Payload Validation Error")
+		assert.Contains(t, rec.Body.String(), `"token":"120%"`)
+		assert.Contains(t, rec.Body.String(), `"path":"projects[0].percentage"`)
+	})
+
 	t.Run("fail - error during saving", func(t *testing.T) {
 		// Setup
 		rdmp := createStubRoadmap()
@@ -292,6 +419,56 @@ func Test_handler_createRoadmapJSON(t *testing.T) {
 	})
 }
 
+func Test_handler_createRoadmapMermaid(t *testing.T) {
+	t.Run("fail - chart errors", func(t *testing.T) {
+		// Setup
+		body := "gantt\n    section Backend\n    API :after db, 2w\n"
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/roadmaps/mermaid?title=Launch", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+
+		h, _ := setupHandler()
+
+		// Run
+		err := h.CreateRoadmapMermaid(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Roadmap Payload Parsing Error")
+		assert.Contains(t, rec.Body.String(), "task db not found")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// Setup
+		body := "gantt\n    dateFormat YYYY-MM-DD\n    section Backend\n    API :a1, 2020-02-01, 2w\n    Storage :after a1, 1w\n"
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/api/roadmaps/mermaid?title=Launch", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Create", mock.AnythingOfType("roadmap.Roadmap")).
+			Return(nil)
+
+		// Run
+		err := h.CreateRoadmapMermaid(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Launch")
+		assert.Contains(t, rec.Body.String(), "Storage")
+		drwMock.AssertExpectations(t)
+	})
+}
+
 func Test_handler_getRoadmapHTML(t *testing.T) {
 	t.Run("fail - not found", func(t *testing.T) {
 		// Setup
@@ -387,6 +564,43 @@ func Test_handler_getRoadmapHTML(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), "</html>")
 		drwMock.AssertExpectations(t)
 	})
+
+	t.Run("success - included projects are not editable, missing includes are reported", func(t *testing.T) {
+		// Setup
+		var teamID, missingID uint64 = 8, 9
+		rdmp := createStubRoadmap()
+		rdmp.Projects = append(rdmp.Projects, Project{Title: "Team", Include: code.Uint64ToString(teamID)}, Project{Title: "Gone", Include: code.Uint64ToString(missingID)})
+		team := &Roadmap{ID: teamID, Projects: []Project{{Title: "Team project", Description: "Shared with the department"}}}
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/abc", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier")
+		ctx.SetParamNames("identifier")
+		ctx.SetParamValues(code.Uint64ToString(rdmp.ID))
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", code.Code64(rdmp.ID)).
+			Return(rdmp, nil)
+		drwMock.
+			On("GetLatest", code.Code64(teamID)).
+			Return(team, nil)
+		drwMock.
+			On("GetLatest", code.Code64(missingID)).
+			Return(nil, nil)
+
+		// Run
+		err := h.GetRoadmapHTML(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Shared with the department")
+		assert.NotContains(t, rec.Body.String(), "\tTeam project")
+		assert.Contains(t, rec.Body.String(), "included roadmap not found")
+		drwMock.AssertExpectations(t)
+	})
 }
 
 func Test_handler_createRoadmapHTML(t *testing.T) {
@@ -396,7 +610,7 @@ func Test_handler_createRoadmapHTML(t *testing.T) {
 
 		f := make(url.Values)
 		f.Set("title", rdmp.Title)
-		f.Set("txt", rdmp.String())
+		f.Set("txt", rdmp.toString(settingTitle, settingDateFormat, settingBaseURL))
 		f.Set("dateFormat", rdmp.DateFormat)
 		f.Set("baseUrl", rdmp.BaseURL)
 
@@ -426,7 +640,7 @@ func Test_handler_createRoadmapHTML(t *testing.T) {
 
 		f := make(url.Values)
 		f.Set("title", rdmp.Title)
-		f.Set("txt", rdmp.String())
+		f.Set("txt", rdmp.toString(settingTitle, settingDateFormat, settingBaseURL))
 		f.Set("dateFormat", rdmp.DateFormat)
 		f.Set("baseUrl", rdmp.BaseURL)
 
@@ -454,7 +668,7 @@ func Test_handler_createRoadmapHTML(t *testing.T) {
 
 		f := make(url.Values)
 		f.Set("title", "")
-		f.Set("txt", rdmp.String())
+		f.Set("txt", rdmp.toString(settingTitle, settingDateFormat, settingBaseURL))
 		f.Set("dateFormat", rdmp.DateFormat)
 		f.Set("baseUrl", rdmp.BaseURL)
 		f.Set("ts", "20")
@@ -477,13 +691,43 @@ func Test_handler_createRoadmapHTML(t *testing.T) {
 		assert.NotEmpty(t, rec.Body.String())
 	})
 
+	t.Run("error - roadmap diagnostics", func(t *testing.T) {
+		// Setup
+		rdmp := createStubRoadmap()
+
+		f := make(url.Values)
+		f.Set("title", rdmp.Title)
+		f.Set("txt", "foo [2020-01-40]")
+		f.Set("dateFormat", rdmp.DateFormat)
+		f.Set("baseUrl", rdmp.BaseURL)
+		f.Set("ts", "20")
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(f.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/")
+
+		h, _ := setupHandler()
+
+		// Run
+		err := h.CreateRoadmapHTML(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Line 1, column 6: invalid date")
+		assert.Contains(t, rec.Body.String(), "foo [2020-01-40]")
+	})
+
 	t.Run("error - writing database", func(t *testing.T) {
 		// Setup
 		rdmp := createStubRoadmap()
 
 		f := make(url.Values)
 		f.Set("title", rdmp.Title)
-		f.Set("txt", rdmp.String())
+		f.Set("txt", rdmp.toString(settingTitle, settingDateFormat, settingBaseURL))
 		f.Set("dateFormat", rdmp.DateFormat)
 		f.Set("baseUrl", rdmp.BaseURL)
 		f.Set("ts", "20")
@@ -516,7 +760,7 @@ func Test_handler_createRoadmapHTML(t *testing.T) {
 
 		f := make(url.Values)
 		f.Set("title", rdmp.Title)
-		f.Set("txt", rdmp.String())
+		f.Set("txt", rdmp.toString(settingTitle, settingDateFormat, settingBaseURL))
 		f.Set("dateFormat", rdmp.DateFormat)
 		f.Set("baseUrl", rdmp.BaseURL)
 		f.Set("ts", "20")
@@ -542,6 +786,37 @@ func Test_handler_createRoadmapHTML(t *testing.T) {
 		assert.Empty(t, rec.Body.String())
 		drwMock.AssertExpectations(t)
 	})
+
+	t.Run("success - warnings are displayed", func(t *testing.T) {
+		// Setup
+		f := make(url.Values)
+		f.Set("title", "Warnings")
+		f.Set("txt", "Project [2020-02-01, 2020-02-14, tbd]")
+		f.Set("dateFormat", "2006-01-02")
+		f.Set("ts", "20")
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(f.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Create", mock.AnythingOfType("Roadmap")).
+			Return(nil)
+
+		// Run
+		err := h.CreateRoadmapHTML(ctx)
+
+		// Assertions
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Line 1, column 34: "+errUnknownExtra.Error())
+		assert.NotContains(t, rec.Body.String(), `action=""`)
+		drwMock.AssertExpectations(t)
+	})
 }
 
 func Test_handler_getPrevID(t *testing.T) {
@@ -787,6 +1062,32 @@ func Test_handler_getRoadmapImage(t *testing.T) {
 		assert.NotEmpty(t, rec.Body.String())
 	})
 
+	t.Run("error - reference date not valid", func(t *testing.T) {
+		// Setup
+		rdmp := &Roadmap{DateFormat: "2006-01-02"}
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/abc/svg?referenceDate=yesterday", nil)
+		req.Header.Set(echo.HeaderContentType, echo.MIMETextHTML)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/:format")
+		ctx.SetParamNames("identifier", "format")
+		ctx.SetParamValues("abc", "svg")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", mock.AnythingOfType("code.Code64")).
+			Return(rdmp, nil)
+
+		// Run
+		err := h.GetRoadmapImage(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "reference date is not valid", rec.Body.String())
+	})
+
 	t.Run("success - empty roadmap", func(t *testing.T) {
 		// Setup
 		rdmp := &Roadmap{}
@@ -844,6 +1145,134 @@ func Test_handler_getRoadmapImage(t *testing.T) {
 		assert.Equal(t, rec.Body.Bytes(), testutils.LoadFile(t, "golden_files", "nonempty.svg"))
 	})
 
+	t.Run("success - missing includes are skipped", func(t *testing.T) {
+		// Setup
+		var missingID uint64 = 9
+		rdmp := createStubRoadmap()
+		rdmp.Projects = append(rdmp.Projects, Project{Title: "Gone", Include: code.Uint64ToString(missingID)})
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/"+code.Uint64ToString(rdmp.ID)+"/mermaid", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/:format")
+		ctx.SetParamNames("identifier", "format")
+		ctx.SetParamValues(code.Uint64ToString(rdmp.ID), "mermaid")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", code.Code64(rdmp.ID)).
+			Return(rdmp, nil)
+		drwMock.
+			On("GetLatest", code.Code64(missingID)).
+			Return(nil, nil)
+
+		// Run
+		err := h.GetRoadmapImage(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Gone")
+		drwMock.AssertExpectations(t)
+	})
+
+	t.Run("success - non-empty roadmap ICS", func(t *testing.T) {
+		// Setup
+		rdmp := createStubRoadmap()
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/abc/ics?projects=true", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/:format")
+		ctx.SetParamNames("identifier", "format")
+		ctx.SetParamValues("abc", "ics")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", mock.AnythingOfType("code.Code64")).
+			Return(rdmp, nil)
+
+		// Run
+		err := h.GetRoadmapImage(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/calendar; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "BEGIN:VCALENDAR\r\n"))
+		assert.Contains(t, rec.Body.String(), "SUMMARY:"+rdmp.Projects[0].Title)
+		drwMock.AssertExpectations(t)
+	})
+
+	t.Run("success - ICS UIDs are derived from the first version", func(t *testing.T) {
+		// Setup
+		var prevID, rootID uint64 = 123, 7
+		rdmp := createStubRoadmap()
+		rdmp.ID = 124
+		rdmp.PrevID = &prevID
+		prev := createStubRoadmap()
+		prev.ID = prevID
+		prev.PrevID = &rootID
+		root := createStubRoadmap()
+		root.ID = rootID
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/"+code.Uint64ToString(rdmp.ID)+"/ics", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/:format")
+		ctx.SetParamNames("identifier", "format")
+		ctx.SetParamValues(code.Uint64ToString(rdmp.ID), "ics")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", code.Code64(rdmp.ID)).
+			Return(rdmp, nil)
+		drwMock.
+			On("Get", code.Code64(prevID)).
+			Return(prev, nil)
+		drwMock.
+			On("Get", code.Code64(rootID)).
+			Return(root, nil)
+
+		// Run
+		err := h.GetRoadmapImage(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "UID:"+code.Uint64ToString(rootID)+"-m-")
+		drwMock.AssertExpectations(t)
+	})
+
+	t.Run("success - previous versions are only loaded for ICS", func(t *testing.T) {
+		// Setup
+		var prevID uint64 = 123
+		rdmp := createStubRoadmap()
+		rdmp.ID = 124
+		rdmp.PrevID = &prevID
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/"+code.Uint64ToString(rdmp.ID)+"/svg", nil)
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetPath("/:identifier/:format")
+		ctx.SetParamNames("identifier", "format")
+		ctx.SetParamValues(code.Uint64ToString(rdmp.ID), "svg")
+
+		h, drwMock := setupHandler()
+		drwMock.
+			On("Get", code.Code64(rdmp.ID)).
+			Return(rdmp, nil)
+
+		// Run
+		err := h.GetRoadmapImage(ctx)
+		require.NoError(t, err)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, rec.Code)
+		drwMock.AssertExpectations(t)
+		drwMock.AssertNumberOfCalls(t, "Get", 1)
+	})
+
 	t.Run("success - non-empty roadmap PNG", func(t *testing.T) {
 		// Setup
 		rdmp := createStubRoadmap()
@@ -0,0 +1,227 @@
+package roadmap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/peteraba/roadmapper/pkg/colors"
+)
+
+// headerDelimiter is the line which opens and closes the header of a Content
+const headerDelimiter = "---"
+
+const (
+	settingTitle      = "title"
+	settingDateFormat = "dateFormat"
+	settingBaseURL    = "baseUrl"
+	settingTheme      = "theme"
+	settingProgress   = "progress"
+	settingTimezone   = "timezone"
+)
+
+// defaultDateFormat is the date format used if none is provided
+const defaultDateFormat = "2006-01-02"
+
+// Settings represents the roadmap-level settings which can be provided in the header of a Content
+type Settings struct {
+	Title      string
+	DateFormat string
+	BaseURL    string
+	Theme      string
+	Progress   ProgressStrategy
+	Timezone   string
+	// Comments are the comments found in the header, they are kept so that they can be written back
+	Comments []string
+}
+
+// apply sets the settings of a roadmap provided in the header of a Content
+// values set explicitly (e.g. via form fields or command line flags) take precedence, header values are only used for
+// settings the roadmap does not have a value for yet
+func (s Settings) apply(r *Roadmap) {
+	if r.Title == "" {
+		r.Title = s.Title
+	}
+
+	if r.DateFormat == "" {
+		r.DateFormat = s.DateFormat
+	}
+
+	if r.BaseURL == "" {
+		r.BaseURL = s.BaseURL
+	}
+
+	if r.Theme == "" {
+		r.Theme = s.Theme
+	}
+
+	if r.Progress == "" {
+		r.Progress = s.Progress
+	}
+
+	if r.Timezone == "" {
+		r.Timezone = s.Timezone
+	}
+
+	if len(r.HeaderComments) == 0 {
+		r.HeaderComments = s.Comments
+	}
+}
+
+// splitHeader separates the header from the rest of a Content
+// the lines of the header are replaced by empty lines in the Content returned, so that line numbers don't change
+// the header is optional, it has to start in the first non-empty line with "---" and it is closed by another "---"
+// comments in the header are collected, so that they can be written back, see headerLines
+func (c Content) splitHeader() (Settings, Content, Diagnostics) {
+	var (
+		s     Settings
+		ds    Diagnostics
+		lines = c.ToLines()
+		start = -1
+	)
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if strings.TrimSpace(line) == headerDelimiter {
+			start = i
+		}
+
+		break
+	}
+
+	if start < 0 {
+		return s, c, nil
+	}
+
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == headerDelimiter {
+			end = i
+			break
+		}
+	}
+
+	if end < 0 {
+		ds = append(ds, Diagnostic{Line: start + 1, Column: 1, Severity: SeverityError, Message: "header is not closed, expected a line with " + headerDelimiter, Token: headerDelimiter})
+
+		return s, c, ds
+	}
+
+	for i := start + 1; i < end; i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, commentPrefix) {
+			s.Comments = append(s.Comments, strings.TrimSpace(line[len(commentPrefix):]))
+			continue
+		}
+
+		var err error
+
+		s, err = s.parseSetting(line)
+		if err != nil {
+			ds = append(ds, newDiagnostic(i+1, strings.Index(lines[i], line)+1, err, line))
+		}
+	}
+
+	for i := start; i <= end; i++ {
+		lines[i] = ""
+	}
+
+	return s, Content(strings.Join(lines, "\n")), ds
+}
+
+// parseSetting parses a line of the header of a Content (e.g. "title: Roadmapper")
+// keys are case insensitive, therefore "baseUrl" and "baseURL" are both accepted
+func (s Settings) parseSetting(line string) (Settings, error) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return s, fmt.Errorf("invalid setting, expected format: key: value")
+	}
+
+	key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	switch strings.ToLower(key) {
+	case strings.ToLower(settingTitle):
+		s.Title = value
+	case strings.ToLower(settingDateFormat):
+		s.DateFormat = value
+	case strings.ToLower(settingBaseURL):
+		s.BaseURL = value
+	case strings.ToLower(settingTheme):
+		t, err := colors.NewTheme(value)
+		if err != nil {
+			return s, err
+		}
+
+		s.Theme = string(t)
+	case strings.ToLower(settingProgress):
+		ps, err := parseProgressStrategy(value)
+		if err != nil {
+			return s, err
+		}
+
+		s.Progress = ps
+	case strings.ToLower(settingTimezone):
+		if _, err := ParseTimezone(value); err != nil {
+			return s, err
+		}
+
+		s.Timezone = value
+	default:
+		return s, errUnknownSetting
+	}
+
+	return s, nil
+}
+
+// headerLines returns the lines of the header representing the settings of a roadmap
+// the title, base URL, theme and timezone are only included if set, the date format and the progress strategy are only included
+// if they are not the default ones
+// settings listed in exclude are never included, comments of the header are written before the settings
+func (r Roadmap) headerLines(exclude ...string) []string {
+	var (
+		lines    []string
+		excluded = map[string]bool{}
+	)
+
+	for _, c := range r.HeaderComments {
+		lines = append(lines, commentLine(c))
+	}
+
+	for _, key := range exclude {
+		excluded[key] = true
+	}
+
+	settings := []struct {
+		key, value string
+		include    bool
+	}{
+		{settingTitle, r.Title, r.Title != ""},
+		{settingDateFormat, r.DateFormat, r.DateFormat != "" && r.DateFormat != defaultDateFormat},
+		{settingBaseURL, r.BaseURL, r.BaseURL != ""},
+		{settingTheme, r.Theme, r.Theme != ""},
+		{settingProgress, string(r.Progress), r.Progress != "" && r.Progress != ProgressAverage},
+		{settingTimezone, r.Timezone, r.Timezone != ""},
+	}
+
+	for _, setting := range settings {
+		if !setting.include || excluded[setting.key] {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s: %s", setting.key, setting.value))
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	lines = append([]string{headerDelimiter}, lines...)
+
+	return append(lines, headerDelimiter)
+}
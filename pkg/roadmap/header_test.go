@@ -0,0 +1,148 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContent_splitHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     Content
+		want  Settings
+		want1 Content
+		want2 Diagnostics
+	}{
+		{
+			"no header",
+			"foo\nbar",
+			Settings{},
+			"foo\nbar",
+			nil,
+		},
+		{
+			"delimiter not in the first line is not a header",
+			"foo\n---\ntitle: bar\n---",
+			Settings{},
+			"foo\n---\ntitle: bar\n---",
+			nil,
+		},
+		{
+			"header",
+			"\n---\ntitle: Roadmapper: the tool\ndateFormat: 02.01.2006\nBaseURL: https://example.com/\n\ntheme: Cool\nprogress: Duration\ntimezone: Europe/Berlin\n---\nfoo",
+			Settings{Title: "Roadmapper: the tool", DateFormat: "02.01.2006", BaseURL: "https://example.com/", Theme: "cool", Progress: ProgressDuration, Timezone: "Europe/Berlin"},
+			"\n\n\n\n\n\n\n\n\n\nfoo",
+			nil,
+		},
+		{
+			"comments",
+			"---\n// owned by the platform team\ntitle: foo\n  //   see the wiki  \n//\n---\nfoo",
+			Settings{Title: "foo", Comments: []string{"owned by the platform team", "see the wiki", ""}},
+			"\n\n\n\n\n\nfoo",
+			nil,
+		},
+		{
+			"invalid and unknown settings",
+			"---\ntitle\n  width: 1000\nprogress: random\ntimezone: Mars/Olympus_Mons\n---\nfoo",
+			Settings{},
+			"\n\n\n\n\n\nfoo",
+			Diagnostics{
+				{Line: 2, Column: 1, Severity: SeverityError, Message: "invalid setting, expected format: key: value", Token: "title"},
+				{Line: 3, Column: 3, Severity: SeverityWarning, Message: "unknown setting, it will be ignored", Token: "width: 1000"},
+				{Line: 4, Column: 1, Severity: SeverityError, Message: "unknown progress strategy, expected one of: average, duration, weight", Token: "progress: random"},
+				{Line: 5, Column: 1, Severity: SeverityError, Message: "unknown timezone, expected a name from the IANA time zone database (e.g. Europe/Berlin)", Token: "timezone: Mars/Olympus_Mons"},
+			},
+		},
+		{
+			"header not closed",
+			"---\ntitle: foo\nbar",
+			Settings{},
+			"---\ntitle: foo\nbar",
+			Diagnostics{
+				{Line: 1, Column: 1, Severity: SeverityError, Message: "header is not closed, expected a line with ---", Token: "---"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1, got2 := tt.c.splitHeader()
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+			assert.Equal(t, tt.want2, got2)
+		})
+	}
+}
+
+func TestRoadmap_headerLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       Roadmap
+		exclude []string
+		want    []string
+	}{
+		{
+			"default settings",
+			Roadmap{DateFormat: "2006-01-02", Progress: ProgressAverage},
+			nil,
+			nil,
+		},
+		{
+			"all settings",
+			Roadmap{Title: "foo", DateFormat: "02.01.2006", BaseURL: "https://example.com/", Theme: "cool", Progress: ProgressWeight, Timezone: "Europe/Berlin"},
+			nil,
+			[]string{"---", "title: foo", "dateFormat: 02.01.2006", "baseUrl: https://example.com/", "theme: cool", "progress: weight", "timezone: Europe/Berlin", "---"},
+		},
+		{
+			"excluded settings",
+			Roadmap{Title: "foo", DateFormat: "02.01.2006", BaseURL: "https://example.com/", Theme: "cool"},
+			[]string{settingTitle, settingDateFormat, settingBaseURL},
+			[]string{"---", "theme: cool", "---"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.r.headerLines(tt.exclude...))
+		})
+	}
+}
+
+func TestRoadmap_String_headerComments(t *testing.T) {
+	c := Content("---\n// owned by the platform team\ntitle: foo\n//\n---\nBackend")
+
+	r, ds := c.Parse(0, nil, "", "", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Equal(t, []string{"owned by the platform team", ""}, r.HeaderComments)
+	assert.Equal(t, "---\n// owned by the platform team\n//\ntitle: foo\n---\nBackend", r.String())
+}
+
+func TestSettings_apply(t *testing.T) {
+	s := Settings{Title: "header", DateFormat: "02.01.2006", BaseURL: "https://example.com/", Theme: "mono", Progress: ProgressWeight, Timezone: "Europe/Berlin"}
+
+	tests := []struct {
+		name string
+		r    Roadmap
+		want Roadmap
+	}{
+		{
+			"header values are used for missing settings",
+			Roadmap{},
+			Roadmap{Title: "header", DateFormat: "02.01.2006", BaseURL: "https://example.com/", Theme: "mono", Progress: ProgressWeight, Timezone: "Europe/Berlin"},
+		},
+		{
+			"explicit values take precedence",
+			Roadmap{Title: "explicit", DateFormat: "2006-01-02", Theme: "warm"},
+			Roadmap{Title: "explicit", DateFormat: "2006-01-02", BaseURL: "https://example.com/", Theme: "warm", Progress: ProgressWeight, Timezone: "Europe/Berlin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.r
+			s.apply(&r)
+
+			assert.Equal(t, tt.want, r)
+		})
+	}
+}
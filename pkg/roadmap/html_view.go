@@ -17,14 +17,14 @@ var dateFormats = []string{
 	"2006/01/02",
 	"02.01.2006",
 	"02/01/2006",
-	"01/02/2020",
-	"01.02.2020",
+	"01/02/2006",
+	"01.02.2006",
 	"2006-1-2",
 	"2006/1/2",
 	"2.1.2006",
 	"2/1/2006",
-	"1/2/2020",
-	"1.2.2020",
+	"1/2/2006",
+	"1.2.2006",
 }
 var dateFormatMap = map[string]string{
 	"2006-01-02": "YYYY-MM-DD (2020-03-17)",
@@ -32,17 +32,19 @@ var dateFormatMap = map[string]string{
 	"2006/01/02": "YYYY/MM/DD (2020/03/17)",
 	"02.01.2006": "DD.MM.YYYY (17.03.2020)",
 	"02/01/2006": "DD/MM/YYYY (17/03/2020)",
-	"01/02/2020": "MM/DD/YYYY (03/17/2020)",
-	"01.02.2020": "MM/DD/YYYY (03.17.2020)",
+	"01/02/2006": "MM/DD/YYYY (03/17/2020)",
+	"01.02.2006": "MM.DD.YYYY (03.17.2020)",
 	"2006-1-2":   "YYYY-M-D (2020-3-7)",
 	"2006/1/2":   "YYYY/M/D (2020/3/7)",
 	"2.1.2006":   "D.M.YYYY (7.3.2020)",
 	"2/1/2006":   "D/M/YYYY (7/3/2020)",
-	"1/2/2020":   "M/D/YYYY (3/7/2020)",
-	"1.2.2020":   "M/D/YYYY (3.7.2020)",
+	"1/2/2006":   "M/D/YYYY (3/7/2020)",
+	"1.2.2006":   "M.D.YYYY (3.7.2020)",
 }
 
-func (r *Roadmap) viewHtml(appVersion, matomoDomain, docBaseURL, currentURL string, selfHosted bool, origErr error) (string, error) {
+// viewHtml renders the HTML view of a roadmap
+// if unsavedRaw is provided, the roadmap is treated as not persisted and unsavedRaw is displayed as its content
+func (r *Roadmap) viewHtml(appVersion, matomoDomain, docBaseURL, currentURL string, selfHosted bool, unsavedRaw string, ds Diagnostics, origErr error) (string, error) {
 	writer := bytes.NewBufferString("")
 
 	layoutTemplate := bindata.MustAsset("res/templates/index.html")
@@ -60,17 +62,24 @@ func (r *Roadmap) viewHtml(appVersion, matomoDomain, docBaseURL, currentURL stri
 		raw          string
 		hasRoadmap   bool
 		projectURLs  = r.getProjectURLs()
+		descriptions = r.getDescriptions()
 	)
 
 	if r != nil {
 		dateFormat = r.DateFormat
 		baseURL = r.BaseURL
-		raw = string(r.ToContent())
+		// settings available as form fields are not repeated in the header, included projects are resolved on load
+		raw = r.withoutIncluded().toString(settingTitle, settingDateFormat, settingBaseURL)
 		hasRoadmap = true
 		pageTitle = r.Title
 		roadmapTitle = r.Title
 	}
 
+	if unsavedRaw != "" {
+		raw = unsavedRaw
+		hasRoadmap = false
+	}
+
 	data := struct {
 		MatomoDomain  string
 		DocBaseURL    string
@@ -86,6 +95,8 @@ func (r *Roadmap) viewHtml(appVersion, matomoDomain, docBaseURL, currentURL stri
 		DateFormatMap map[string]string
 		Version       string
 		ProjectURLs   map[string][]string
+		Descriptions  []describedItem
+		Diagnostics   Diagnostics
 		Error         error
 	}{
 		MatomoDomain:  matomoDomain,
@@ -102,6 +113,8 @@ func (r *Roadmap) viewHtml(appVersion, matomoDomain, docBaseURL, currentURL stri
 		DateFormatMap: dateFormatMap,
 		Version:       appVersion,
 		ProjectURLs:   projectURLs,
+		Descriptions:  descriptions,
+		Diagnostics:   ds,
 		Error:         origErr,
 	}
 
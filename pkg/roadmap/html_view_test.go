@@ -25,6 +25,8 @@ func TestRoadmap_viewHtml(t *testing.T) {
 		docBaseURL   string
 		currentURL   string
 		selfHosted   bool
+		unsavedRaw   string
+		ds           Diagnostics
 		err          error
 	}
 	tests := []struct {
@@ -50,7 +52,7 @@ func TestRoadmap_viewHtml(t *testing.T) {
 				UpdatedAt:  tt.fields.UpdatedAt,
 				AccessedAt: tt.fields.AccessedAt,
 			}
-			got, err := r.viewHtml(tt.args.appVersion, tt.args.matomoDomain, tt.args.docBaseURL, tt.args.currentURL, tt.args.selfHosted, tt.args.err)
+			got, err := r.viewHtml(tt.args.appVersion, tt.args.matomoDomain, tt.args.docBaseURL, tt.args.currentURL, tt.args.selfHosted, tt.args.unsavedRaw, tt.args.ds, tt.args.err)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("viewHtml() error = %v, wantErr %v", err, tt.wantErr)
 				return
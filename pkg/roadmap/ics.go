@@ -0,0 +1,207 @@
+package roadmap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+const (
+	icsDateFormat     = "20060102"
+	icsDateTimeFormat = "20060102T150405"
+	icsUIDDomain      = "rdmp.app"
+	icsLineLength     = 75
+)
+
+// icsTextReplacer escapes characters which have a special meaning in iCalendar text values
+var icsTextReplacer = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// ApplyCalendarProjects sets whether projects are exported as calendar events next to milestones, see ToICS
+func (vr *VisualRoadmap) ApplyCalendarProjects(enabled bool) *VisualRoadmap {
+	vr.CalendarProjects = enabled
+
+	return vr
+}
+
+// ApplyCalendarCode sets the code used for deriving the UIDs of calendar events, it should be the same for all versions
+// of a roadmap, see ToICS
+func (vr *VisualRoadmap) ApplyCalendarCode(c string) *VisualRoadmap {
+	vr.Code = c
+
+	return vr
+}
+
+// ToICS converts a visual roadmap into an iCalendar file
+// milestones with a deadline become all-day events, projects with dates become multi-day events if CalendarProjects
+// is set, events with a time of day are converted to UTC using the timezone of the roadmap
+// UIDs are derived from the code of the roadmap and the keys, IDs or titles of events, so that calendar clients update
+// events instead of duplicating them, see ApplyCalendarCode
+func (vr *VisualRoadmap) ToICS(now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Roadmapper//Roadmapper//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+
+	if vr.Title != "" {
+		lines = append(lines, "X-WR-CALNAME:"+icsText(vr.Title))
+	}
+
+	var (
+		stamp = now.UTC().Format(icsDateTimeFormat) + "Z"
+		keys  = map[string]int{}
+	)
+
+	for _, m := range vr.Milestones {
+		if m.DeadlineAt == nil {
+			continue
+		}
+
+		key := icsKey(keys, "m", m.Key, m.Title)
+		title := expandTitleTemplate(vr.TitleTemplate, m.Title, m.Fields)
+
+		lines = append(lines, vr.icsEvent(key, stamp, title, *m.DeadlineAt, *m.DeadlineAt, m.Description, m.URLs)...)
+	}
+
+	if vr.CalendarProjects {
+		seen := map[int]bool{}
+		for i, p := range vr.Projects {
+			// lane headers and projects repeated in multiple swimlanes must not result in (duplicated) events
+			if _, first := vr.origin(i, seen); !first || p.Dates == nil {
+				continue
+			}
+
+			key := icsKey(keys, "p", p.ID, p.Title)
+			title := expandTitleTemplate(vr.TitleTemplate, p.Title, p.Fields)
+
+			lines = append(lines, vr.icsEvent(key, stamp, title, p.Dates.StartAt, p.Dates.EndAt, p.Description, p.URLs)...)
+		}
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	var folded []string
+	for _, line := range lines {
+		folded = append(folded, icsFold(line))
+	}
+
+	return strings.Join(folded, "\r\n") + "\r\n"
+}
+
+// icsKey returns a key identifying an event within a roadmap, derived from the key or ID of a milestone or project if
+// set, from its title otherwise, keys already used get a counter appended
+func icsKey(keys map[string]int, prefix, id, title string) string {
+	key := prefix + "-" + id
+	if id == "" {
+		key = prefix + "-" + icsHash(title)
+	}
+
+	keys[key]++
+	if keys[key] > 1 {
+		key = fmt.Sprintf("%s-%d", key, keys[key])
+	}
+
+	return key
+}
+
+// icsHash returns a short, stable hash of a string
+func icsHash(s string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// icsEvent returns the lines of an event, events without a time of day are all-day events
+// the end of all-day events is exclusive, so the day after the last day of the event is used
+func (vr *VisualRoadmap) icsEvent(key, stamp, title string, startAt, endAt time.Time, description string, urls []string) []string {
+	start, end := vr.icsDate(startAt), vr.icsDate(endAt)
+
+	if isMidnight(startAt) && isMidnight(endAt) {
+		if endAt.Before(startAt) {
+			endAt = startAt
+		}
+
+		start, end = ";VALUE=DATE:"+startAt.Format(icsDateFormat), ";VALUE=DATE:"+endAt.AddDate(0, 0, 1).Format(icsDateFormat)
+	}
+
+	// roadmaps rendered via the cli have no code, the hash of their title is used instead
+	prefix := vr.Code
+	if prefix == "" {
+		prefix = icsHash(vr.Title)
+	}
+
+	uid := prefix + "-" + key
+
+	lines := []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:%s@%s", icsText(uid), icsUIDDomain),
+		"DTSTAMP:" + stamp,
+		"DTSTART" + start,
+		"DTEND" + end,
+		"SUMMARY:" + icsText(title),
+	}
+
+	var details []string
+	if description != "" {
+		details = append(details, description)
+	}
+	details = append(details, urls...)
+
+	if len(details) > 0 {
+		lines = append(lines, "DESCRIPTION:"+icsText(strings.Join(details, "\n")))
+	}
+
+	if len(urls) > 0 {
+		lines = append(lines, "URL:"+urls[0])
+	}
+
+	return append(lines, "END:VEVENT")
+}
+
+// icsDate formats a date with a time of day, wall clock times of the roadmap timezone are converted to UTC, dates are
+// left floating if the roadmap has no timezone
+func (vr *VisualRoadmap) icsDate(t time.Time) string {
+	if vr.Timezone == nil {
+		return ":" + t.Format(icsDateTimeFormat)
+	}
+
+	local := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, vr.Timezone)
+
+	return ":" + local.UTC().Format(icsDateTimeFormat) + "Z"
+}
+
+// isMidnight returns true if a date has no time of day set
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+}
+
+// icsText escapes a text value of an iCalendar property
+func icsText(s string) string {
+	return icsTextReplacer.Replace(s)
+}
+
+// icsFold splits lines longer than 75 octets into multiple lines as required by RFC 5545
+// lines are only split between runes, continuation lines start with a space
+func icsFold(line string) string {
+	var (
+		b      strings.Builder
+		length int
+	)
+
+	for _, r := range line {
+		n := len(string(r))
+		if length+n > icsLineLength {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+
+		b.WriteRune(r)
+		length += n
+	}
+
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package roadmap
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisualRoadmap_ToICS(t *testing.T) {
+	now := time.Date(2020, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	c := Content(`Backend [2020-02-01, 2020-02-15, https://example.com/backend, id:be]
+	Demo [2020-02-10 09:00, 2020-02-10 11:30]
+		> Live demo; all teams
+Undated
+
+|Beta [2020-03-01, https://example.com/beta, id:beta]
+|Launch [2020-04-01]
+|Later`)
+
+	tests := []struct {
+		name     string
+		projects bool
+		timezone string
+		want     []string
+	}{
+		{
+			"milestones only",
+			false,
+			"",
+			[]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//Roadmapper//Roadmapper//EN",
+				"CALSCALE:GREGORIAN",
+				"METHOD:PUBLISH",
+				"X-WR-CALNAME:Launch\\, v1",
+				"BEGIN:VEVENT",
+				"UID:ABCDEFGHIJ-m-beta@rdmp.app",
+				"DTSTAMP:20200115T103000Z",
+				"DTSTART;VALUE=DATE:20200301",
+				"DTEND;VALUE=DATE:20200302",
+				"SUMMARY:Beta",
+				"DESCRIPTION:https://example.com/beta",
+				"URL:https://example.com/beta",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:ABCDEFGHIJ-m-37cbb286@rdmp.app",
+				"DTSTAMP:20200115T103000Z",
+				"DTSTART;VALUE=DATE:20200401",
+				"DTEND;VALUE=DATE:20200402",
+				"SUMMARY:Launch",
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+		{
+			"projects in timezone",
+			true,
+			"Europe/Berlin",
+			[]string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"PRODID:-//Roadmapper//Roadmapper//EN",
+				"CALSCALE:GREGORIAN",
+				"METHOD:PUBLISH",
+				"X-WR-CALNAME:Launch\\, v1",
+				"BEGIN:VEVENT",
+				"UID:ABCDEFGHIJ-m-beta@rdmp.app",
+				"DTSTAMP:20200115T103000Z",
+				"DTSTART;VALUE=DATE:20200301",
+				"DTEND;VALUE=DATE:20200302",
+				"SUMMARY:Beta",
+				"DESCRIPTION:https://example.com/beta",
+				"URL:https://example.com/beta",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:ABCDEFGHIJ-m-37cbb286@rdmp.app",
+				"DTSTAMP:20200115T103000Z",
+				"DTSTART;VALUE=DATE:20200401",
+				"DTEND;VALUE=DATE:20200402",
+				"SUMMARY:Launch",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:ABCDEFGHIJ-p-be@rdmp.app",
+				"DTSTAMP:20200115T103000Z",
+				"DTSTART;VALUE=DATE:20200201",
+				"DTEND;VALUE=DATE:20200216",
+				"SUMMARY:Backend",
+				"DESCRIPTION:https://example.com/backend",
+				"URL:https://example.com/backend",
+				"END:VEVENT",
+				"BEGIN:VEVENT",
+				"UID:ABCDEFGHIJ-p-15dccc56@rdmp.app",
+				"DTSTAMP:20200115T103000Z",
+				"DTSTART:20200210T080000Z",
+				"DTEND:20200210T103000Z",
+				"SUMMARY:Demo",
+				`DESCRIPTION:Live demo\; all teams`,
+				"END:VEVENT",
+				"END:VCALENDAR",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ds := c.Parse(0, nil, "Launch, v1", "2006-01-02", "", now)
+			require.False(t, ds.HasErrors(), ds.Error())
+
+			tz, err := ParseTimezone(tt.timezone)
+			require.NoError(t, err)
+
+			vr := r.ToVisual().ApplyTimezone(tz).ApplyCalendarProjects(tt.projects).ApplyCalendarCode("ABCDEFGHIJ")
+
+			assert.Equal(t, strings.Join(tt.want, "\r\n")+"\r\n", vr.ToICS(now))
+		})
+	}
+}
+
+func TestVisualRoadmap_ToICS_UIDs(t *testing.T) {
+	now := time.Date(2020, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	c := Content(`Backend [2020-02-01, 2020-02-15, @peter, @anna]
+	API [2020-02-01, 2020-02-07]
+Backend [2020-02-16, 2020-02-28]
+Frontend [2020-03-01, 2020-03-31, @anna]`)
+
+	r, ds := c.Parse(0, nil, "Launch", "2006-01-02", "", now)
+	require.False(t, ds.HasErrors(), ds.Error())
+
+	tests := []struct {
+		name   string
+		layout Layout
+		want   []string
+	}{
+		{
+			"default layout",
+			DefaultLayout,
+			[]string{
+				"UID:37cbb286-p-56cdb9ef@rdmp.app",
+				"DTSTART;VALUE=DATE:20200201",
+				"DTEND;VALUE=DATE:20200216",
+				"UID:37cbb286-p-7aa6f0e7@rdmp.app",
+				"DTSTART;VALUE=DATE:20200201",
+				"DTEND;VALUE=DATE:20200208",
+				"UID:37cbb286-p-56cdb9ef-2@rdmp.app",
+				"DTSTART;VALUE=DATE:20200216",
+				"DTEND;VALUE=DATE:20200229",
+				"UID:37cbb286-p-35e2889d@rdmp.app",
+				"DTSTART;VALUE=DATE:20200301",
+				"DTEND;VALUE=DATE:20200401",
+			},
+		},
+		{
+			"swimlanes skip lane headers and projects repeated in multiple swimlanes",
+			SwimlanesLayout,
+			[]string{
+				"UID:37cbb286-p-56cdb9ef@rdmp.app",
+				"DTSTART;VALUE=DATE:20200201",
+				"DTEND;VALUE=DATE:20200216",
+				"UID:37cbb286-p-7aa6f0e7@rdmp.app",
+				"DTSTART;VALUE=DATE:20200201",
+				"DTEND;VALUE=DATE:20200208",
+				"UID:37cbb286-p-35e2889d@rdmp.app",
+				"DTSTART;VALUE=DATE:20200301",
+				"DTEND;VALUE=DATE:20200401",
+				"UID:37cbb286-p-56cdb9ef-2@rdmp.app",
+				"DTSTART;VALUE=DATE:20200216",
+				"DTEND;VALUE=DATE:20200229",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := r.ToVisual().ApplyLayout(tt.layout).ApplyCalendarProjects(true)
+
+			var got []string
+			for _, line := range strings.Split(vr.ToICS(now), "\r\n") {
+				if strings.HasPrefix(line, "UID:") || strings.HasPrefix(line, "DTSTART") || strings.HasPrefix(line, "DTEND") {
+					got = append(got, line)
+				}
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_icsFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 40)
+
+	got := icsFold(line)
+
+	for _, l := range strings.Split(got, "\r\n") {
+		assert.LessOrEqual(t, len(l), icsLineLength)
+	}
+	assert.Equal(t, line, strings.ReplaceAll(got, "\r\n ", ""))
+}
@@ -3,12 +3,13 @@ package roadmap
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/tdewolff/canvas"
 
 	"github.com/peteraba/roadmapper/pkg/bindata"
-	"github.com/peteraba/roadmapper/pkg/colors"
 )
 
 var fontFamily *canvas.FontFamily
@@ -17,14 +18,11 @@ var myDarkGray = color.RGBA{R: 95, G: 95, B: 95, A: 255}
 var defaultMilestoneColor = &canvas.Darkgray
 
 // Draw will draw a roadmap on a canvas.Canvas
-func (vr *VisualRoadmap) Draw(fullW, lineH float64, withToday bool) *canvas.Canvas {
-	headerH := 0.0
-	if vr.Dates != nil {
-		headerH = lineH * 3
-	}
-
+// if withToday is true, a line marks the reference date, the current day in the timezone of the roadmap if zero
+func (vr *VisualRoadmap) Draw(fullW, lineH float64, withToday bool, referenceAt time.Time) *canvas.Canvas {
+	headerH := vr.headerHeight(lineH)
 	strokeW := 2.0
-	fullH := lineH*float64(len(vr.Projects)) + headerH
+	fullH := vr.imageHeight(lineH)
 
 	fontFamily = canvas.NewFontFamily("roboto")
 	font, err := bindata.Asset("res/fonts/Roboto/Roboto-Regular.ttf")
@@ -54,13 +52,43 @@ func (vr *VisualRoadmap) Draw(fullW, lineH float64, withToday bool) *canvas.Canv
 
 	vr.drawLines(ctx, fullW, fullH, headerH, lineH)
 
-	vr.drawToday(ctx, fullW, fullH, lineH, withToday)
+	vr.drawDependencies(ctx, fullW, fullH, headerH, lineH, strokeW)
+
+	vr.drawToday(ctx, fullW, fullH, lineH, withToday, referenceAt)
 
 	vr.writeTitle(ctx, fullW, fullH, lineH)
 
 	return c
 }
 
+// headerHeight returns the height of the header displaying the dates of the roadmap
+func (vr *VisualRoadmap) headerHeight(lineH float64) float64 {
+	if vr.Dates == nil {
+		return 0.0
+	}
+
+	return lineH * 3
+}
+
+// imageHeight returns the height of the whole image, including the header
+func (vr *VisualRoadmap) imageHeight(lineH float64) float64 {
+	return lineH*float64(len(vr.Projects)) + vr.headerHeight(lineH)
+}
+
+// rowTop returns the canvas coordinate of the top of the row of the i-th project
+// note that canvas coordinates grow from the bottom of the image
+func rowTop(fullH, headerH, lineH float64, i int) float64 {
+	return fullH - headerH - float64(i)*lineH
+}
+
+// dateX returns the horizontal position of a date, the last two thirds of the image being the timeline of the roadmap
+func (vr *VisualRoadmap) dateX(t time.Time, fullW float64) float64 {
+	maxW := fullW * 2 / 3
+	roadmapInterval := vr.Dates.EndAt.Sub(vr.Dates.StartAt).Hours()
+
+	return t.Sub(vr.Dates.StartAt).Hours()/roadmapInterval*maxW + fullW/3
+}
+
 func (vr *VisualRoadmap) drawBackground(ctx *canvas.Context, fullW, fullH, headerH float64) {
 	p := &canvas.Path{}
 	p.MoveTo(0, 0)
@@ -101,11 +129,11 @@ func (vr *VisualRoadmap) writeHeaderDates(ctx *canvas.Context, fullW, fullH, lin
 	x := fullW / 3
 	y := fullH
 	face := fontFamily.Face(lineH*1.5, canvas.Black, canvas.FontBold, canvas.FontNormal)
-	date := vr.Dates.StartAt.Format(vr.DateFormat)
+	date, endDate := vr.headerLabels()
 	ctx.DrawText(x, y, canvas.NewTextBox(face, date, 0.0, lineH, canvas.Left, canvas.Center, 0.0, 0.0))
 
 	x = fullW
-	date = vr.Dates.EndAt.Format(vr.DateFormat)
+	date = endDate
 	ctx.DrawText(x, y, canvas.NewTextBox(face, date, 0.0, lineH, canvas.Right, canvas.Center, 0.0, 0.0))
 }
 
@@ -131,33 +159,71 @@ func (vr *VisualRoadmap) writeProjects(ctx *canvas.Context, fullW, fullH, header
 	ctx.SetFillColor(canvas.Black)
 
 	for i, p := range vr.Projects {
-		y := fullH - float64(i)*lineH - headerH
+		y := rowTop(fullH, headerH, lineH, i)
 		indentW := float64(p.Indentation)*textW/20 + 2
-		font := vr.createFont(p.Indentation, lineH)
-		ctx.DrawText(0, y, canvas.NewTextBox(font, p.Title, textW, lineH, canvas.Left, canvas.Center, indentW, 0.0))
+
+		var deco []canvas.FontDecorator
+		if p.Status == StatusCancelled {
+			deco = append(deco, canvas.FontStrikethrough)
+		}
+
+		font := vr.createFont(p.Indentation, lineH, deco...)
+		ctx.DrawText(0, y, canvas.NewTextBox(font, expandTitleTemplate(vr.TitleTemplate, p.Title, p.Fields), textW, lineH, canvas.Left, canvas.Center, indentW, 0.0))
+
+		if label := vr.sideLabel(i); label != "" {
+			sideFont := fontFamily.Face(lineH*0.9, myDarkGray, canvas.FontRegular, canvas.FontNormal)
+			ctx.DrawText(0, y, canvas.NewTextBox(sideFont, label, textW-4, lineH, canvas.Right, canvas.Center, 0.0, 0.0))
+		}
+	}
+}
+
+// sideLabel creates the label displayed at the right side of the title of a project, listing its owners and the
+// estimates of parent projects
+func (vr *VisualRoadmap) sideLabel(i int) string {
+	var parts []string
+
+	if label := vr.estimateLabel(i); label != "" {
+		parts = append(parts, label)
+	}
+
+	if len(vr.Projects[i].Owners) > 0 {
+		parts = append(parts, ownersLabel(vr.Projects[i].Owners))
+	}
+
+	return strings.Join(parts, "  ")
+}
+
+// ownersLabel creates the label listing the owners of a project (e.g. "@peter, @anna")
+func ownersLabel(owners []string) string {
+	var handles []string
+
+	for _, o := range owners {
+		handles = append(handles, ownerPrefix+o)
 	}
+
+	return strings.Join(handles, ", ")
 }
 
-func (vr *VisualRoadmap) createFont(indentation uint8, lineH float64) canvas.FontFace {
+func (vr *VisualRoadmap) createFont(indentation uint8, lineH float64, deco ...canvas.FontDecorator) canvas.FontFace {
 	switch indentation {
 	case 0:
 		fontSize := lineH * 1.5
-		return fontFamily.Face(fontSize, canvas.Black, canvas.FontBold, canvas.FontNormal)
+		return fontFamily.Face(fontSize, canvas.Black, canvas.FontBold, canvas.FontNormal, deco...)
 	case 1:
 		fontSize := lineH * 1.5
-		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal, deco...)
 	case 2:
 		fontSize := lineH * 1.35
-		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal, deco...)
 	case 3:
 		fontSize := lineH * 1.2
-		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal, deco...)
 	case 4:
 		fontSize := lineH * 1.05
-		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal, deco...)
 	default:
 		fontSize := lineH * 0.9
-		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+		return fontFamily.Face(fontSize, canvas.Black, canvas.FontRegular, canvas.FontNormal, deco...)
 	}
 }
 
@@ -167,8 +233,6 @@ func (vr *VisualRoadmap) drawProjects(ctx *canvas.Context, fullW, fullH, headerH
 	}
 
 	h := lineH / 2
-	maxW := fullW * 2 / 3
-	roadmapInterval := vr.Dates.EndAt.Sub(vr.Dates.StartAt).Hours()
 	r := lineH / 5
 
 	ctx.SetStrokeWidth(1.0)
@@ -179,20 +243,135 @@ func (vr *VisualRoadmap) drawProjects(ctx *canvas.Context, fullW, fullH, headerH
 			continue
 		}
 
-		startAtLeft := p.Dates.StartAt.Sub(vr.Dates.StartAt).Hours()
-		endAtLeft := p.Dates.EndAt.Sub(vr.Dates.StartAt).Hours()
-		x0 := startAtLeft / roadmapInterval * maxW
-		x1 := endAtLeft / roadmapInterval * maxW
+		x0 := vr.dateX(p.Dates.StartAt, fullW)
+		x1 := vr.dateX(p.Dates.EndAt, fullW)
 		w := x1 - x0
-		y := fullH - float64(i)*lineH - headerH - lineH/4*3
+		y := rowTop(fullH, headerH, lineH, i) - lineH/4*3
+
+		switch {
+		case len(p.Phases) > 0:
+			vr.drawPhases(ctx, p, x0, y, w, h, r)
+		default:
+			ctx.SetFillColor(myLightGrey)
+			ctx.DrawPath(x0, y, canvas.RoundedRectangle(w, h, r))
+
+			if p.Percentage > 0 {
+				ctx.SetFillColor(p.Color)
+				ctx.DrawPath(x0, y, canvas.RoundedRectangle(w*float64(p.Percentage)/100, h, r))
+			}
+
+			if p.Status == StatusBlocked {
+				vr.drawHatching(ctx, x0, y, w, h)
+			}
+		}
+
+		switch p.Status {
+		case StatusAtRisk:
+			vr.drawWarningMarker(ctx, x0+w-h*3/2, y, h)
+		}
+	}
+
+	ctx.SetStrokeWidth(strokeW)
+}
 
-		ctx.SetFillColor(myLightGrey)
-		ctx.DrawPath(x0+fullW/3, y, canvas.RoundedRectangle(w, h, r))
+// drawHatching draws diagonal lines over the bar of a project (e.g. to mark it as blocked)
+func (vr *VisualRoadmap) drawHatching(ctx *canvas.Context, x, y, w, h float64) {
+	gap := h / 2
 
-		if p.Percentage > 0 {
-			w *= float64(p.Percentage) / 100
-			ctx.SetFillColor(p.Color)
-			ctx.DrawPath(x0+fullW/3, y, canvas.RoundedRectangle(w, h, r))
+	p := &canvas.Path{}
+	for offset := -h; offset < w; offset += gap {
+		// lines go from bottom left to top right, they are clipped to the bar horizontally
+		t0 := math.Max(0, -offset)
+		t1 := math.Min(h, w-offset)
+		if t1 <= t0 {
+			continue
+		}
+
+		p.MoveTo(offset+t0, t0)
+		p.LineTo(offset+t1, t1)
+	}
+
+	ctx.SetStrokeColor(myDarkGray)
+	ctx.SetFillColor(canvas.Transparent)
+	ctx.DrawPath(x, y, p)
+	ctx.SetStrokeColor(canvas.Darkgray)
+}
+
+// drawWarningMarker draws a warning sign at the end of the bar of a project (e.g. to mark it as at risk)
+func (vr *VisualRoadmap) drawWarningMarker(ctx *canvas.Context, x, y, h float64) {
+	p := &canvas.Path{}
+	p.MoveTo(0, 0)
+	p.LineTo(h, 0)
+	p.LineTo(h/2, h)
+	p.Close()
+
+	ctx.SetFillColor(canvas.Orange)
+	ctx.DrawPath(x+h/4, y, p)
+
+	face := fontFamily.Face(h*2, canvas.Black, canvas.FontBold, canvas.FontNormal)
+	ctx.DrawText(x+h/4, y+h, canvas.NewTextBox(face, "!", h, h, canvas.Center, canvas.Center, 0.0, 0.0))
+}
+
+// drawDependencies draws arrows from the end of the bars of projects to the start of the bars of the projects depending
+// on them
+func (vr *VisualRoadmap) drawDependencies(ctx *canvas.Context, fullW, fullH, headerH, lineH, strokeW float64) {
+	if vr.Dates == nil {
+		return
+	}
+
+	offset := lineH / 4
+	arrowW := lineH / 5
+
+	ctx.SetStrokeWidth(1.0)
+	ctx.SetStrokeColor(myDarkGray)
+
+	for i, p := range vr.Projects {
+		if p.Dates == nil {
+			continue
+		}
+
+		for _, j := range vr.dependenciesOf(i) {
+			if vr.Projects[j].Dates == nil {
+				continue
+			}
+
+			pred := vr.Projects[j]
+
+			x0 := vr.dateX(pred.Dates.EndAt, fullW)
+			y0 := rowTop(fullH, headerH, lineH, j) - lineH/2
+			x1 := vr.dateX(p.Dates.StartAt, fullW)
+			y1 := rowTop(fullH, headerH, lineH, i) - lineH/2
+
+			path := &canvas.Path{}
+			path.MoveTo(x0, y0)
+			path.LineTo(x0+offset, y0)
+
+			if x1-arrowW-offset >= x0+offset {
+				path.LineTo(x0+offset, y1)
+			} else {
+				// the dependent project starts before its predecessor ends, the arrow has to go around
+				yMid := y0 - lineH/2
+				if y1 > y0 {
+					yMid = y0 + lineH/2
+				}
+
+				path.LineTo(x0+offset, yMid)
+				path.LineTo(x1-arrowW-offset, yMid)
+				path.LineTo(x1-arrowW-offset, y1)
+			}
+
+			path.LineTo(x1-arrowW, y1)
+
+			arrow := &canvas.Path{}
+			arrow.MoveTo(x1, y1)
+			arrow.LineTo(x1-arrowW, y1+arrowW/2)
+			arrow.LineTo(x1-arrowW, y1-arrowW/2)
+			arrow.Close()
+
+			ctx.SetFillColor(canvas.Transparent)
+			ctx.DrawPath(0, 0, path)
+			ctx.SetFillColor(myDarkGray)
+			ctx.DrawPath(0, 0, arrow)
 		}
 	}
 
@@ -204,9 +383,7 @@ func (vr *VisualRoadmap) drawMilestones(ctx *canvas.Context, fullW, fullH, heade
 		return
 	}
 
-	maxW := fullW * 2 / 3
 	y := fullH - headerH/3
-	roadmapInterval := vr.Dates.EndAt.Sub(vr.Dates.StartAt).Hours()
 
 	ctx.SetDashes(0.0, 3.0, 3.0)
 	for _, m := range vr.Milestones {
@@ -219,30 +396,31 @@ func (vr *VisualRoadmap) drawMilestones(ctx *canvas.Context, fullW, fullH, heade
 			c = *m.Color
 		}
 
-		deadlineFromStart := m.DeadlineAt.Sub(vr.Dates.StartAt).Hours()
-		w := deadlineFromStart / roadmapInterval * maxW
+		x := vr.dateX(*m.DeadlineAt, fullW)
 
 		p := &canvas.Path{}
-		p.MoveTo(w, 0)
-		p.LineTo(w, fullH)
+		p.MoveTo(x, 0)
+		p.LineTo(x, fullH)
 
 		ctx.SetStrokeColor(c)
-		ctx.DrawPath(fullW/3, 0, p)
+		ctx.DrawPath(0, 0, p)
 
-		x := w + fullW/3
 		face := fontFamily.Face(lineH*1.5, c, canvas.FontRegular, canvas.FontNormal)
-		date := fmt.Sprintf("%s\n%s", m.DeadlineAt.Format(vr.DateFormat), m.Title)
+		date := fmt.Sprintf("%s\n%s", m.DeadlineAt.Format(vr.DateFormat), expandTitleTemplate(vr.TitleTemplate, m.Title, m.Fields))
 		ctx.DrawText(x, y, canvas.NewTextBox(face, date, 0.0, lineH*2, canvas.Center, canvas.Center, 0.0, 0.0))
 	}
 	ctx.SetDashes(0.0)
 }
 
-func (vr *VisualRoadmap) drawToday(ctx *canvas.Context, fullW, fullH, lineH float64, withToday bool) {
+func (vr *VisualRoadmap) drawToday(ctx *canvas.Context, fullW, fullH, lineH float64, withToday bool, referenceAt time.Time) {
 	if vr.Dates == nil || !withToday {
 		return
 	}
 
-	now := time.Now()
+	now := referenceAt
+	if now.IsZero() {
+		now = wallClock(time.Now(), vr.Timezone)
+	}
 	if vr.Dates.StartAt.After(now) || vr.Dates.EndAt.Before(now) {
 		return
 	}
@@ -255,21 +433,16 @@ func (vr *VisualRoadmap) drawToday(ctx *canvas.Context, fullW, fullH, lineH floa
 		return
 	}
 
-	maxW := fullW * 2 / 3
-	roadmapInterval := vr.Dates.EndAt.Sub(vr.Dates.StartAt).Hours()
-
-	todayFromStart := now.Sub(vr.Dates.StartAt).Hours()
-	w := todayFromStart / roadmapInterval * maxW
+	x := vr.dateX(now, fullW)
 
 	p := &canvas.Path{}
-	p.MoveTo(w, 0)
-	p.LineTo(w, fullH)
+	p.MoveTo(x, 0)
+	p.LineTo(x, fullH)
 
 	ctx.SetStrokeColor(myDarkGray)
 	ctx.SetDashes(0.0, 8.0, 12.0)
-	ctx.DrawPath(fullW/3, 0, p)
+	ctx.DrawPath(0, 0, p)
 
-	x := w + fullW/3
 	y := fullH
 	face := fontFamily.Face(lineH*1.5, myDarkGray, canvas.FontRegular, canvas.FontNormal)
 	date := now.Format(vr.DateFormat)
@@ -298,7 +471,7 @@ func (vr *VisualRoadmap) drawProjectLines(ctx *canvas.Context, fullW, fullH, hea
 	var paths []*canvas.Path
 
 	for i := range vr.Projects {
-		h := fullH - headerH - (float64(i) * lineH)
+		h := rowTop(fullH, headerH, lineH, i)
 
 		p := &canvas.Path{}
 		p.MoveTo(0, h)
@@ -320,12 +493,12 @@ func (vr *VisualRoadmap) drawProjectBackgrounds(ctx *canvas.Context, fullW, full
 	var epicCount = -1
 
 	for i := range vr.Projects {
-		h0 := fullH - headerH - (float64(i+1) * (lineH))
-		h1 := h0 + lineH
+		h1 := rowTop(fullH, headerH, lineH, i)
+		h0 := h1 - lineH
 
 		if vr.Projects[i].Indentation == 0 {
 			epicCount++
-			c1 = colors.PickBgColor(epicCount)
+			c1 = vr.Theme.PickBgColor(epicCount)
 		}
 
 		p := &canvas.Path{}
@@ -0,0 +1,188 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/peteraba/roadmapper/pkg/code"
+)
+
+// includePrefix starts the code of a stored roadmap included under a project (e.g. include:abc123)
+const includePrefix = "include:"
+
+// maxIncludeDepth is the maximum number of nested includes resolved
+const maxIncludeDepth = 5
+
+var (
+	errCannotParseInclude = errors.New("can not parse string as include, expected the code of a roadmap (e.g. include:abc123)")
+	errIncludeCycle       = errors.New("roadmap includes itself")
+	errIncludeDepth       = fmt.Errorf("too many nested includes, at most %d levels are supported", maxIncludeDepth)
+)
+
+// roadmapLoader loads the latest version of a stored roadmap by its code, see DbReadWriter.GetLatest
+type roadmapLoader func(identifier string) (*Roadmap, error)
+
+// parseInclude tries to parse a string as the code of an included roadmap (e.g. include:abc123)
+func parseInclude(part string) (string, error) {
+	identifier := strings.TrimSpace(part[len(includePrefix):])
+	if identifier == "" {
+		return "", errCannotParseInclude
+	}
+
+	if _, err := code.NewCode64FromString(identifier); err != nil {
+		return "", errCannotParseInclude
+	}
+
+	return identifier, nil
+}
+
+// resolveIncludes returns a copy of the roadmap in which the projects of included roadmaps are added as sub-projects
+// of the projects including them
+// includes are not persisted, they are resolved each time a roadmap is loaded so that the latest version of the
+// included roadmaps is shown, even if the code included belongs to an older version of them, projects and milestones added are marked with the code of the roadmap included
+// includes which can not be resolved are skipped and reported as warnings referring to the project including them
+func (r Roadmap) resolveIncludes(load roadmapLoader) (Roadmap, Diagnostics) {
+	return r.resolveIncludesFrom(load, []string{code.Uint64ToString(r.ID)})
+}
+
+// resolveIncludesFrom resolves includes recursively, path contains the codes of the roadmaps including this one
+func (r Roadmap) resolveIncludesFrom(load roadmapLoader, path []string) (Roadmap, Diagnostics) {
+	var (
+		ds         Diagnostics
+		projects   []Project
+		milestones = append([]Milestone(nil), r.Milestones...)
+	)
+
+	for i, p := range r.Projects {
+		projects = append(projects, p)
+
+		if p.Include == "" {
+			continue
+		}
+
+		included, err := loadIncluded(load, path, p.Include)
+		if err != nil {
+			ds = append(ds, Diagnostic{Path: projectPath(i), Severity: SeverityWarning, Message: err.Error(), Token: includePrefix + p.Include})
+			continue
+		}
+
+		resolved, ds2 := included.resolveIncludesFrom(load, append(path[:len(path):len(path)], loadedCode(included, p.Include)))
+		// problems of nested includes are reported for the project including them in this roadmap
+		for _, d := range ds2 {
+			d.Path = projectPath(i)
+			ds = append(ds, d)
+		}
+
+		// included projects are added before the existing sub-projects of the including project
+		projects = append(projects, spliceProjects(resolved, p.Include, p.Indentation+1, len(milestones))...)
+		for _, m := range resolved.Milestones {
+			m.IncludedFrom = p.Include
+			milestones = append(milestones, m)
+		}
+	}
+
+	r.Projects = projects
+	r.Milestones = milestones
+
+	return r, ds
+}
+
+// loadIncluded loads a roadmap included by the last roadmap of a path of includes
+func loadIncluded(load roadmapLoader, path []string, identifier string) (*Roadmap, error) {
+	if containsString(path, identifier) {
+		return nil, fmt.Errorf("%w: %s", errIncludeCycle, strings.Join(append(path, identifier), " -> "))
+	}
+
+	if len(path) > maxIncludeDepth {
+		return nil, errIncludeDepth
+	}
+
+	included, err := load(identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load included roadmap %s: %w", identifier, err)
+	}
+
+	if included == nil {
+		return nil, fmt.Errorf("included roadmap not found: %s", identifier)
+	}
+
+	// the latest version of a roadmap might include an older version of a roadmap including it
+	if latest := loadedCode(included, identifier); latest != identifier && containsString(path, latest) {
+		return nil, fmt.Errorf("%w: %s", errIncludeCycle, strings.Join(append(path, latest), " -> "))
+	}
+
+	return included, nil
+}
+
+// loadedCode returns the code of the version of an included roadmap loaded, which is the code included unless a later
+// version of the roadmap was found
+func loadedCode(included *Roadmap, identifier string) string {
+	if included.ID == 0 {
+		return identifier
+	}
+
+	return code.Uint64ToString(included.ID)
+}
+
+// withoutIncluded returns a copy of the roadmap without the projects and milestones added by resolving includes
+// included milestones always follow the milestones of the roadmap, so milestone positions of the rest stay valid
+func (r Roadmap) withoutIncluded() Roadmap {
+	var (
+		projects   []Project
+		milestones []Milestone
+	)
+
+	for _, p := range r.Projects {
+		if p.IncludedFrom == "" {
+			projects = append(projects, p)
+		}
+	}
+
+	for _, m := range r.Milestones {
+		if m.IncludedFrom == "" {
+			milestones = append(milestones, m)
+		}
+	}
+
+	r.Projects = projects
+	r.Milestones = milestones
+
+	return r
+}
+
+// spliceProjects prepares the projects of an included roadmap to be added to another roadmap
+// projects are indented below the including project, milestone references are turned into positions after the
+// milestones of the including roadmap, and IDs are made unique so that dependencies can not point outside the included
+// roadmap
+func spliceProjects(included Roadmap, identifier string, indentation uint8, milestoneOffset int) []Project {
+	projects := make([]Project, len(included.Projects))
+
+	uniqueID := func(i int) string {
+		return identifier + ":" + strconv.Itoa(i)
+	}
+
+	for i, p := range included.Projects {
+		p.Indentation += indentation
+		p.ID = uniqueID(i)
+		p.IncludedFrom = identifier
+
+		p.Milestone, p.MilestoneRef = 0, ""
+		if mk := findMilestone(included.Milestones, included.Projects[i]); mk >= 0 && milestoneOffset+mk < 255 {
+			p.Milestone = uint8(milestoneOffset + mk + 1)
+		}
+
+		var dependsOn []string
+		for _, ref := range p.DependsOn {
+			if j := findDependency(included.Projects, i, ref); j >= 0 {
+				dependsOn = append(dependsOn, uniqueID(j))
+			}
+		}
+		p.DependsOn = dependsOn
+
+		projects[i] = p
+	}
+
+	return projects
+}
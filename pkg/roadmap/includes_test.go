@@ -0,0 +1,196 @@
+package roadmap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peteraba/roadmapper/pkg/code"
+)
+
+func Test_parseInclude(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    string
+		wantErr error
+	}{
+		{"code", "include:abc", "abc", nil},
+		{"empty", "include:", "", errCannotParseInclude},
+		{"invalid code", "include:a b", "", errCannotParseInclude},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseInclude(tt.part)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContent_Parse_include(t *testing.T) {
+	c := Content("Department\n\tTeam A [include:abc]\n|Release [include:abc]")
+
+	r, ds := c.Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	require.Len(t, ds, 1)
+	assert.Equal(t, "milestones only support a deadline, a color, urls, fields and an id", ds[0].Message)
+	assert.Equal(t, "abc", r.Projects[1].Include)
+	assert.Equal(t, "\tTeam A [include:abc]", r.Projects[1].String("2006-01-02"))
+}
+
+func TestRoadmap_resolveIncludes(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 14, 0, 0, 0, 0, time.UTC)
+	)
+
+	stored := map[string]*Roadmap{
+		"teamA": {
+			Projects: []Project{
+				{Title: "Backend", ID: "be", MilestoneRef: "beta"},
+				{Title: "API", Indentation: 1, Dates: &Dates{StartAt: d1, EndAt: d2}},
+				{Title: "Frontend", DependsOn: []string{"be"}, Include: "teamC"},
+			},
+			Milestones: []Milestone{{Title: "Beta", Key: "beta"}},
+		},
+		"teamC": {
+			Projects: []Project{{Title: "Design"}},
+		},
+		"loop": {
+			Projects: []Project{{Title: "Loop", Include: "loop"}},
+		},
+	}
+
+	load := func(identifier string) (*Roadmap, error) {
+		r, ok := stored[identifier]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+
+		return r, nil
+	}
+
+	t.Run("projects and milestones are spliced", func(t *testing.T) {
+		r := Roadmap{
+			Projects: []Project{
+				{Title: "Department"},
+				{Title: "Team A", Indentation: 1, Include: "teamA"},
+				{Title: "Team B", Indentation: 1, Milestone: 1},
+			},
+			Milestones: []Milestone{{Title: "Launch"}},
+		}
+
+		got, ds := r.resolveIncludes(load)
+		assert.Empty(t, ds)
+
+		assert.Equal(t, []Project{
+			{Title: "Department"},
+			{Title: "Team A", Indentation: 1, Include: "teamA"},
+			{Title: "Backend", Indentation: 2, ID: "teamA:0", Milestone: 2, IncludedFrom: "teamA"},
+			{Title: "API", Indentation: 3, ID: "teamA:1", Dates: &Dates{StartAt: d1, EndAt: d2}, IncludedFrom: "teamA"},
+			{Title: "Frontend", Indentation: 2, ID: "teamA:2", DependsOn: []string{"teamA:0"}, Include: "teamC", IncludedFrom: "teamA"},
+			{Title: "Design", Indentation: 3, ID: "teamA:3", IncludedFrom: "teamA"},
+			{Title: "Team B", Indentation: 1, Milestone: 1},
+		}, got.Projects)
+		assert.Equal(t, []Milestone{{Title: "Launch"}, {Title: "Beta", Key: "beta", IncludedFrom: "teamA"}}, got.Milestones)
+
+		// the original roadmap is kept intact
+		assert.Len(t, r.Projects, 3)
+
+		// included projects and milestones can be dropped again
+		assert.Equal(t, r, got.withoutIncluded())
+	})
+
+	t.Run("dependencies don't point to included projects", func(t *testing.T) {
+		r := Roadmap{
+			ID: 1,
+			Projects: []Project{
+				{Title: "Team C", Include: "teamC"},
+				{Title: "Design"},
+				{Title: "Build", DependsOn: []string{"Design"}},
+			},
+		}
+
+		got, ds := r.resolveIncludes(load)
+		assert.Empty(t, ds)
+
+		assert.Equal(t, []Project{
+			{Title: "Team C", Include: "teamC"},
+			{Title: "Design", Indentation: 1, ID: "teamC:0", IncludedFrom: "teamC"},
+			{Title: "Design"},
+			{Title: "Build", DependsOn: []string{"Design"}},
+		}, got.Projects)
+		assert.Equal(t, [][]int{nil, nil, nil, {2}}, got.ToVisual().dependencies)
+
+		// projects of the roadmap itself are not changed
+		assert.Equal(t, r, got.withoutIncluded())
+	})
+
+	t.Run("latest version", func(t *testing.T) {
+		latest := &Roadmap{ID: 12, Projects: []Project{{Title: "Latest"}}}
+		r := Roadmap{ID: 1, Projects: []Project{{Title: "Team", Include: code.Uint64ToString(11)}}}
+
+		got, ds := r.resolveIncludes(func(identifier string) (*Roadmap, error) { return latest, nil })
+		assert.Empty(t, ds)
+
+		assert.Equal(t, []Project{
+			{Title: "Team", Include: code.Uint64ToString(11)},
+			{Title: "Latest", Indentation: 1, ID: code.Uint64ToString(11) + ":0", IncludedFrom: code.Uint64ToString(11)},
+		}, got.Projects)
+	})
+
+	t.Run("latest version includes an older version", func(t *testing.T) {
+		r := Roadmap{ID: 2, Projects: []Project{{Title: "Older", Include: code.Uint64ToString(1)}}}
+
+		got, ds := r.resolveIncludes(func(identifier string) (*Roadmap, error) { return &r, nil })
+		require.Len(t, ds, 1)
+		assert.Contains(t, ds[0].Message, errIncludeCycle.Error())
+		assert.Equal(t, r.Projects, got.Projects)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		r := Roadmap{Projects: []Project{{Title: "Top"}, {Title: "Loop", Include: "loop"}}}
+
+		got, ds := r.resolveIncludes(load)
+		require.Len(t, ds, 1)
+		assert.Equal(t, "projects[1]", ds[0].Path)
+		assert.Equal(t, SeverityWarning, ds[0].Severity)
+		assert.Contains(t, ds[0].Message, errIncludeCycle.Error())
+		assert.Len(t, got.Projects, 3)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		r := Roadmap{ID: 3, Projects: []Project{{Title: "Self", Include: code.Uint64ToString(3)}}}
+
+		got, ds := r.resolveIncludes(func(identifier string) (*Roadmap, error) { return &r, nil })
+		require.Len(t, ds, 1)
+		assert.Contains(t, ds[0].Message, errIncludeCycle.Error())
+		assert.Equal(t, r.Projects, got.Projects)
+	})
+
+	t.Run("depth limit", func(t *testing.T) {
+		n := 0
+		deeper := func(identifier string) (*Roadmap, error) {
+			n++
+			return &Roadmap{Projects: []Project{{Title: "Level", Include: code.Uint64ToString(uint64(100 + n))}}}, nil
+		}
+
+		r := Roadmap{Projects: []Project{{Title: "Top", Include: "abc"}}}
+
+		_, ds := r.resolveIncludes(deeper)
+		assert.Equal(t, Diagnostics{{Path: "projects[0]", Severity: SeverityWarning, Message: errIncludeDepth.Error(), Token: includePrefix + code.Uint64ToString(uint64(100+n))}}, ds)
+		assert.Equal(t, maxIncludeDepth, n)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := Roadmap{Projects: []Project{{Title: "Missing", Include: "missing"}, {Title: "Other"}}}
+
+		got, ds := r.resolveIncludes(load)
+		assert.Equal(t, Diagnostics{{Path: "projects[0]", Severity: SeverityWarning, Message: "failed to load included roadmap missing: not found", Token: "include:missing"}}, ds)
+		assert.Equal(t, r.Projects, got.Projects)
+	})
+}
@@ -0,0 +1,114 @@
+package roadmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// InputFormat represents the format roadmaps are read from
+type InputFormat string
+
+const (
+	// TextFormat is the indented text format of Content, this is the default
+	TextFormat InputFormat = "txt"
+	// JSONFormat is RoadmapExchange as JSON, as returned by the API
+	JSONFormat InputFormat = "json"
+	// YAMLFormat is RoadmapExchange as YAML, using the same keys as JSONFormat
+	YAMLFormat InputFormat = "yaml"
+	// MermaidInputFormat is a Mermaid gantt chart, as exported by ToMermaid
+	MermaidInputFormat InputFormat = "mermaid"
+	// CSVFormat is the spreadsheet friendly format of ToCSV
+	CSVFormat InputFormat = "csv"
+)
+
+// NewInputFormat returns the input format of the name given, an empty name results in TextFormat
+func NewInputFormat(name string) (InputFormat, error) {
+	switch strings.ToLower(name) {
+	case "", "txt", "text":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	case "yaml", "yml":
+		return YAMLFormat, nil
+	case "mermaid", "mmd":
+		return MermaidInputFormat, nil
+	case "csv":
+		return CSVFormat, nil
+	}
+
+	return "", fmt.Errorf("unsupported input format: %s", name)
+}
+
+// InputFormatByExtension guesses the input format of a file based on its extension, TextFormat is used by default
+func InputFormatByExtension(fileName string) InputFormat {
+	f, err := NewInputFormat(strings.TrimPrefix(filepath.Ext(fileName), "."))
+	if err != nil {
+		return TextFormat
+	}
+
+	return f
+}
+
+// ParseExchange converts RoadmapExchange in JSON or YAML format into a Roadmap
+// the date format and base url given are only used if they are not set in the data, the date format is detected
+// from the expressions of projects if neither is set
+func ParseExchange(data []byte, format InputFormat, dateFormat, baseUrl string) (Roadmap, error) {
+	var err error
+
+	switch format {
+	case JSONFormat:
+	case YAMLFormat:
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return Roadmap{}, fmt.Errorf("invalid yaml: %w", err)
+		}
+	default:
+		return Roadmap{}, fmt.Errorf("unsupported exchange format: %s", format)
+	}
+
+	var re RoadmapExchange
+
+	err = json.Unmarshal(data, &re)
+	if err != nil {
+		return Roadmap{}, fmt.Errorf("invalid roadmap: %w", err)
+	}
+
+	r := re.ToRoadmap()
+
+	if r.DateFormat == "" {
+		r.DateFormat = dateFormat
+	}
+
+	if r.DateFormat == "" {
+		r.DateFormat = r.detectDateFormat().formatOrDefault()
+	}
+
+	if r.BaseURL == "" {
+		r.BaseURL = baseUrl
+	}
+
+	return r, nil
+}
+
+// Encode converts a Roadmap into the format given, the result can be read using the same format
+// Mermaid gantt charts can not hold all information of roadmaps, all other formats are lossless
+func (r Roadmap) Encode(format InputFormat) ([]byte, error) {
+	switch format {
+	case TextFormat:
+		return []byte(r.String()), nil
+	case JSONFormat:
+		return json.MarshalIndent(r.ToExchange(), "", "  ")
+	case YAMLFormat:
+		return yaml.Marshal(r.ToExchange())
+	case MermaidInputFormat:
+		return []byte(r.ToVisual().ToMermaid()), nil
+	case CSVFormat:
+		return []byte(r.ToCSV()), nil
+	}
+
+	return nil, fmt.Errorf("unsupported output format: %s", format)
+}
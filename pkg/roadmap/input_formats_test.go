@@ -0,0 +1,109 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInputFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    InputFormat
+		wantErr bool
+	}{
+		{"", TextFormat, false},
+		{"txt", TextFormat, false},
+		{"JSON", JSONFormat, false},
+		{"yml", YAMLFormat, false},
+		{"xml", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewInputFormat(tt.name)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInputFormatByExtension(t *testing.T) {
+	assert.Equal(t, JSONFormat, InputFormatByExtension("roadmap.json"))
+	assert.Equal(t, YAMLFormat, InputFormatByExtension("dir/roadmap.YML"))
+	assert.Equal(t, TextFormat, InputFormatByExtension("roadmap.txt"))
+	assert.Equal(t, MermaidInputFormat, InputFormatByExtension("roadmap.mmd"))
+	assert.Equal(t, CSVFormat, InputFormatByExtension("roadmap.csv"))
+	assert.Equal(t, TextFormat, InputFormatByExtension(""))
+}
+
+func TestParseExchange(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 14, 0, 0, 0, 0, time.UTC)
+	)
+
+	want := []Project{
+		{Title: "Project", Dates: &Dates{StartAt: d1, EndAt: d2}, Percentage: 20, Fields: Fields{{Key: "team", Value: "payments"}}},
+		{Title: "Sub-project", Indentation: 1, StartExpression: "01.02.2020", EndExpression: "1w"},
+	}
+
+	tests := []struct {
+		name           string
+		data           string
+		format         InputFormat
+		dateFormat     string
+		wantDateFormat string
+		wantBaseURL    string
+	}{
+		{
+			"json",
+			`{"id":"abc","title":"Roadmap","date_format":"2006-01-02","base_url":"https://example.com/","projects":[
+				{"indentation":0,"title":"Project","dates":{"start_at":"2020-02-01T00:00:00Z","end_at":"2020-02-14T00:00:00Z"},"percentage":20,"fields":{"team":"payments"}},
+				{"indentation":1,"title":"Sub-project","start_expression":"01.02.2020","end_expression":"1w","percentage":0}
+			]}`,
+			JSONFormat,
+			"02/01/2006",
+			"2006-01-02",
+			"https://example.com/",
+		},
+		{
+			"yaml without date format",
+			`title: Roadmap
+projects:
+  - title: Project
+    dates:
+      start_at: 2020-02-01T00:00:00Z
+      end_at: 2020-02-14T00:00:00Z
+    percentage: 20
+    fields:
+      team: payments
+  - title: Sub-project
+    indentation: 1
+    start_expression: "01.02.2020"
+    end_expression: 1w
+`,
+			YAMLFormat,
+			"",
+			"02.01.2006",
+			"https://example.org/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExchange([]byte(tt.data), tt.format, tt.dateFormat, "https://example.org/")
+			require.NoError(t, err)
+
+			assert.Equal(t, "Roadmap", got.Title)
+			assert.Equal(t, tt.wantDateFormat, got.DateFormat)
+			assert.Equal(t, tt.wantBaseURL, got.BaseURL)
+			assert.Equal(t, want, got.Projects)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseExchange([]byte("title: [a"), YAMLFormat, "", "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,151 @@
+package roadmap
+
+import (
+	"errors"
+	"strings"
+)
+
+// labelPrefix starts a free-form label of a project (e.g. ~backend)
+const labelPrefix = "~"
+
+var errCannotParseLabel = errors.New("can not parse string as label, expected format: ~label")
+
+// parseLabel tries to parse a string as a label (e.g. ~backend)
+func parseLabel(part string) (string, error) {
+	label := part[len(labelPrefix):]
+	if label == "" || strings.ContainsAny(label, " \t") {
+		return "", errCannotParseLabel
+	}
+
+	return label, nil
+}
+
+// ParseLabelList converts a comma separated list of labels into a slice (e.g. "backend, ~q3-commit")
+// the label prefix is optional, empty labels are skipped
+func ParseLabelList(list string) []string {
+	var labels []string
+
+	for _, l := range strings.Split(list, ",") {
+		l = strings.TrimPrefix(strings.TrimSpace(l), labelPrefix)
+		if l == "" {
+			continue
+		}
+
+		labels = append(labels, l)
+	}
+
+	return labels
+}
+
+// FilterByLabels keeps the projects of a visual roadmap which have at least one of the included labels (if any are
+// given) and none of the excluded ones
+// custom fields can also be used as labels in the form of key=value (e.g. team=payments)
+// projects inherit the labels of their parents and ancestors of matching projects are kept, so that the hierarchy of
+// the remaining projects still makes sense
+func (vr *VisualRoadmap) FilterByLabels(include, exclude []string) *VisualRoadmap {
+	if len(include) == 0 && len(exclude) == 0 {
+		return vr
+	}
+
+	var (
+		keep  = make([]bool, len(vr.Projects))
+		stack []int
+	)
+
+	for i, p := range vr.Projects {
+		for len(stack) > 0 && vr.Projects[stack[len(stack)-1]].Indentation >= p.Indentation {
+			stack = stack[:len(stack)-1]
+		}
+
+		labels := p.filterLabels()
+		for _, j := range stack {
+			labels = append(labels, vr.Projects[j].filterLabels()...)
+		}
+
+		if matchesLabels(labels, include, exclude) {
+			keep[i] = true
+
+			for _, j := range stack {
+				keep[j] = true
+			}
+		}
+
+		stack = append(stack, i)
+	}
+
+	var (
+		projects []Project
+		rows     = make([]int, len(vr.Projects))
+	)
+	for i, p := range vr.Projects {
+		rows[i] = -1
+		if keep[i] {
+			rows[i] = len(projects)
+			projects = append(projects, p)
+		}
+	}
+
+	var dependencies [][]int
+	for i, deps := range vr.dependencies {
+		if !keep[i] {
+			continue
+		}
+
+		var kept []int
+		for _, j := range deps {
+			if rows[j] >= 0 {
+				kept = append(kept, rows[j])
+			}
+		}
+		dependencies = append(dependencies, kept)
+	}
+
+	vr.Projects = projects
+	vr.dependencies = dependencies
+
+	return vr
+}
+
+// filterLabels returns the labels and custom fields of a project usable for filtering
+func (p Project) filterLabels() []string {
+	labels := append([]string{}, p.Labels...)
+
+	for _, f := range p.Fields {
+		labels = append(labels, f.String())
+	}
+
+	return labels
+}
+
+// matchesLabels checks if a set of labels contains at least one of the included labels (if any are given) and none
+// of the excluded ones
+func matchesLabels(labels, include, exclude []string) bool {
+	for _, l := range exclude {
+		if containsLabel(labels, l) {
+			return false
+		}
+	}
+
+	if len(include) == 0 {
+		return true
+	}
+
+	for _, l := range include {
+		if containsLabel(labels, l) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsLabel checks if a label can be found in a list of labels
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package roadmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    string
+		wantErr error
+	}{
+		{"label", "~backend", "backend", nil},
+		{"empty label", "~", "", errCannotParseLabel},
+		{"label with whitespace", "~q3 commit", "", errCannotParseLabel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLabel(tt.part)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseLabelList(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"labels with and without prefix", "backend, ~q3-commit,,", []string{"backend", "q3-commit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseLabelList(tt.list))
+		})
+	}
+}
+
+func TestVisualRoadmap_FilterByLabels(t *testing.T) {
+	projects := []Project{
+		{Title: "Backend", Labels: []string{"backend"}},
+		{Title: "Create API", Indentation: 1, Fields: Fields{{Key: "team", Value: "payments"}}},
+		{Title: "Create DB", Indentation: 1, Labels: []string{"later"}},
+		{Title: "Marketing"},
+		{Title: "Campaigns", Indentation: 1},
+		{Title: "Create blog posts", Indentation: 2, Labels: []string{"q3-commit"}},
+		{Title: "Create Facebook page", Indentation: 2},
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{
+			"no filters",
+			nil,
+			nil,
+			[]string{"Backend", "Create API", "Create DB", "Marketing", "Campaigns", "Create blog posts", "Create Facebook page"},
+		},
+		{
+			"labels are inherited",
+			[]string{"backend"},
+			nil,
+			[]string{"Backend", "Create API", "Create DB"},
+		},
+		{
+			"ancestors are kept",
+			[]string{"q3-commit"},
+			nil,
+			[]string{"Marketing", "Campaigns", "Create blog posts"},
+		},
+		{
+			"exclude",
+			nil,
+			[]string{"later", "q3-commit"},
+			[]string{"Backend", "Create API", "Marketing", "Campaigns", "Create Facebook page"},
+		},
+		{
+			"fields",
+			[]string{"team=payments"},
+			nil,
+			[]string{"Backend", "Create API"},
+		},
+		{
+			"include and exclude",
+			[]string{"backend", "q3-commit"},
+			[]string{"later"},
+			[]string{"Backend", "Create API", "Marketing", "Campaigns", "Create blog posts"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &VisualRoadmap{Projects: projects}
+
+			var got []string
+			for _, p := range vr.FilterByLabels(tt.include, tt.exclude).Projects {
+				got = append(got, p.Title)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,506 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	mermaidDateFormat       = "YYYY-MM-DD"
+	mermaidDateTimeFormat   = "YYYY-MM-DD HH:mm"
+	mermaidMilestoneSection = "Milestones"
+	// mermaidInclusiveEndDates makes Mermaid treat end dates as inclusive, the same way roadmaps do
+	mermaidInclusiveEndDates = "inclusiveEndDates"
+)
+
+// mermaidTitleReplacer removes characters from titles which have a special meaning in Mermaid
+var mermaidTitleReplacer = strings.NewReplacer(":", " -", ";", ",", "#", "", "\n", " ")
+
+// ToMermaid converts a visual roadmap into a Mermaid gantt chart
+// top-level projects become sections, their sub-projects become tasks, milestones are listed in a separate section
+// projects without dates are skipped, as Mermaid needs a start and an end for every task
+// end dates are inclusive unless a time of day is used, in which case they are exact
+func (vr *VisualRoadmap) ToMermaid() string {
+	mermaidFormat, goFormat, inclusive := mermaidDateFormat, defaultDateFormat, true
+	if vr.hasTimeOfDay() {
+		mermaidFormat, goFormat, inclusive = mermaidDateTimeFormat, defaultDateFormat+timeOfDayLayout, false
+	}
+
+	lines := []string{"gantt"}
+
+	if vr.Title != "" {
+		lines = append(lines, "    title "+mermaidTitle(vr.Title))
+	}
+
+	lines = append(lines, "    dateFormat "+mermaidFormat)
+
+	if inclusive {
+		lines = append(lines, "    "+mermaidInclusiveEndDates)
+	}
+
+	for i, p := range vr.Projects {
+		if p.Indentation == 0 {
+			lines = append(lines, "    section "+mermaidTitle(expandTitleTemplate(vr.TitleTemplate, p.Title, p.Fields)))
+
+			if vr.isMermaidSection(i) {
+				continue
+			}
+		}
+
+		if p.Dates == nil {
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("    %s :%s", mermaidTitle(expandTitleTemplate(vr.TitleTemplate, p.Title, p.Fields)), strings.Join(vr.mermaidTask(i, goFormat, inclusive), ", ")))
+	}
+
+	var milestones []string
+	for i, m := range vr.Milestones {
+		if m.DeadlineAt == nil {
+			continue
+		}
+
+		milestones = append(milestones, fmt.Sprintf("    %s :milestone, m%d, %s, 0d", mermaidTitle(expandTitleTemplate(vr.TitleTemplate, m.Title, m.Fields)), i+1, m.DeadlineAt.Format(goFormat)))
+	}
+
+	if len(milestones) > 0 {
+		lines = append(lines, "    section "+mermaidMilestoneSection)
+		lines = append(lines, milestones...)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// mermaidTask returns the parts of a Mermaid task following its title: tags, id, start and end
+// dependencies are used as start if the project starts right after the last of them ends
+func (vr *VisualRoadmap) mermaidTask(i int, goFormat string, inclusive bool) []string {
+	var (
+		p     = vr.Projects[i]
+		parts []string
+	)
+
+	switch {
+	case p.Percentage >= 100:
+		parts = append(parts, "done")
+	case p.Percentage > 0:
+		parts = append(parts, "active")
+	}
+
+	parts = append(parts, fmt.Sprintf("t%d", i+1))
+
+	start := p.Dates.StartAt.Format(goFormat)
+	if after := vr.mermaidAfter(i, inclusive); after != "" {
+		start = after
+	}
+
+	return append(parts, start, p.Dates.EndAt.Format(goFormat))
+}
+
+// mermaidAfter returns an after clause (e.g. after t1 t3) if a project starts when the last of its dependencies ends
+// with inclusive end dates a task after others starts on the day after the last of them ends
+func (vr *VisualRoadmap) mermaidAfter(i int, inclusive bool) string {
+	var (
+		ids    []string
+		latest time.Time
+	)
+
+	for _, j := range vr.dependenciesOf(i) {
+		if vr.Projects[j].Dates == nil || vr.isMermaidSection(j) {
+			return ""
+		}
+
+		ids = append(ids, fmt.Sprintf("t%d", j+1))
+
+		if vr.Projects[j].Dates.EndAt.After(latest) {
+			latest = vr.Projects[j].Dates.EndAt
+		}
+	}
+
+	if inclusive {
+		latest = latest.AddDate(0, 0, 1)
+	}
+
+	if len(ids) == 0 || !latest.Equal(vr.Projects[i].Dates.StartAt) {
+		return ""
+	}
+
+	return "after " + strings.Join(ids, " ")
+}
+
+// hasTimeOfDay returns true if any of the dates of projects or milestones have a time of day set
+func (vr *VisualRoadmap) hasTimeOfDay() bool {
+	isSet := func(t time.Time) bool {
+		return t.Hour() != 0 || t.Minute() != 0
+	}
+
+	for _, p := range vr.Projects {
+		if p.Dates != nil && (isSet(p.Dates.StartAt) || isSet(p.Dates.EndAt)) {
+			return true
+		}
+	}
+
+	for _, m := range vr.Milestones {
+		if m.DeadlineAt != nil && isSet(*m.DeadlineAt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMermaidSection returns true if a project is displayed as a section, meaning that it's a top-level project with
+// sub-projects
+func (vr *VisualRoadmap) isMermaidSection(i int) bool {
+	return vr.Projects[i].Indentation == 0 && i+1 < len(vr.Projects) && vr.Projects[i+1].Indentation > 0
+}
+
+// mermaidTitle makes a title safe to be used in Mermaid
+func mermaidTitle(title string) string {
+	return strings.TrimSpace(mermaidTitleReplacer.Replace(title))
+}
+
+// mermaidLayoutReplacer converts the tokens of Mermaid date formats into the tokens of Go layouts
+var mermaidLayoutReplacer = strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "M", "1", "D", "2")
+
+// mermaidDurationRegexp matches the durations of Mermaid tasks (e.g. 3d, 1w, 12h, 30m)
+var mermaidDurationRegexp = regexp.MustCompile(`^(\d+)([mhdw])$`)
+
+// mermaidTags are the tags which may precede the id and the dates of Mermaid tasks
+var mermaidTags = []string{"done", "active", "crit", "milestone"}
+
+// mermaidDirectives are the keywords of Mermaid gantt charts ignored while parsing
+var mermaidDirectives = []string{"gantt", "axisFormat", "tickInterval", "todayMarker", "weekday", "topAxis", "displayMode", "accTitle", "accDescr"}
+
+// mermaidChart represents the settings of a Mermaid gantt chart used for calculating the dates of tasks
+type mermaidChart struct {
+	layout string
+	// inclusive is true if end dates include the day given (inclusiveEndDates), by default Mermaid end dates are exclusive
+	inclusive bool
+	// excludes and includes are the days (e.g. weekends, friday, 2020-02-14) skipped by durations and the exceptions
+	excludes []string
+	includes []string
+}
+
+// mermaidDaysSplitter splits the days listed by the excludes and includes keywords
+var mermaidDaysSplitter = regexp.MustCompile(`[\s,]+`)
+
+// parseMermaidDays parses the days listed by the excludes and includes keywords (e.g. weekends, 2020-02-14)
+func parseMermaidDays(value string) []string {
+	return mermaidDaysSplitter.Split(strings.ToLower(value), -1)
+}
+
+// isExcluded returns true if a day is skipped by durations, the same way Mermaid skips it
+func (c mermaidChart) isExcluded(d time.Time) bool {
+	date := strings.ToLower(d.Format(c.layout))
+	if containsString(c.includes, date) {
+		return false
+	}
+
+	weekday := d.Weekday()
+	if containsString(c.excludes, "weekends") && (weekday == time.Saturday || weekday == time.Sunday) {
+		return true
+	}
+
+	return containsString(c.excludes, strings.ToLower(weekday.String())) || containsString(c.excludes, date)
+}
+
+// skipExcluded returns the end of a task defined by a duration extended by the days excluded, as well as the end
+// displayed, which does not include the excluded days the task ends with, the same way Mermaid calculates them
+func (c mermaidChart) skipExcluded(startAt, endAt time.Time) (time.Time, time.Time) {
+	if len(c.excludes) == 0 {
+		return endAt, endAt
+	}
+
+	var (
+		displayedEndAt time.Time
+		excluded       bool
+	)
+
+	for d := startAt; !d.After(endAt); d = d.AddDate(0, 0, 1) {
+		if !excluded {
+			displayedEndAt = endAt
+		}
+
+		excluded = c.isExcluded(d)
+		if excluded {
+			endAt = endAt.AddDate(0, 0, 1)
+		}
+	}
+
+	return endAt, displayedEndAt
+}
+
+// toDates converts the dates of a Mermaid task into the dates of a project
+// end dates of roadmaps are inclusive, unless a time of day is used, while Mermaid calculates with exclusive ends
+func (c mermaidChart) toDates(t mermaidTask) *Dates {
+	// 15 is the hour in Go layouts, see mermaidLayoutReplacer
+	if strings.Contains(c.layout, "15") {
+		return &Dates{StartAt: t.startAt, EndAt: t.displayedEndAt}
+	}
+
+	endAt := t.displayedEndAt.AddDate(0, 0, -1)
+	if endAt.Before(t.startAt) {
+		endAt = t.startAt
+	}
+
+	return &Dates{StartAt: t.startAt, EndAt: endAt}
+}
+
+// mermaidAfterPrefix starts the start of Mermaid tasks starting after other tasks (e.g. after t1 t2)
+const mermaidAfterPrefix = "after "
+
+// ParseMermaid converts a Mermaid gantt chart into a Roadmap
+// sections become top-level projects, tasks become their sub-projects and milestone tasks become milestones
+// relative starts (after) and durations are resolved into dates taking inclusiveEndDates, excludes and includes into
+// account, the title of the chart is used only if no title is provided
+func ParseMermaid(content string, id uint64, prevID *uint64, title, baseUrl string, now time.Time) (Roadmap, Diagnostics) {
+	r := Roadmap{
+		ID:         id,
+		PrevID:     prevID,
+		Title:      title,
+		DateFormat: defaultDateFormat,
+		BaseURL:    baseUrl,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		AccessedAt: now,
+	}
+
+	var (
+		ds        Diagnostics
+		chart     = mermaidChart{layout: defaultDateFormat}
+		section   = -1
+		lastEnd   *time.Time
+		ends      = map[string]time.Time{}
+		projectID = map[string]bool{}
+		onlyMS    bool
+	)
+
+	// sections containing only milestones are dropped, as milestones are not listed under projects
+	closeSection := func() {
+		if section >= 0 && section == len(r.Projects)-1 && onlyMS {
+			r.Projects = r.Projects[:section]
+		}
+	}
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "%%") || strings.HasPrefix(line, "```") {
+			continue
+		}
+
+		keyword, value := line, ""
+		if n := strings.IndexAny(line, " \t"); n > 0 {
+			keyword, value = line[:n], strings.TrimSpace(line[n:])
+		}
+
+		switch {
+		case keyword == "title":
+			if title == "" {
+				r.Title = value
+			}
+			continue
+		case keyword == "dateFormat":
+			chart.layout = mermaidLayoutReplacer.Replace(value)
+			if containsString(dateFormats, chart.layout) {
+				r.DateFormat = chart.layout
+			}
+			continue
+		case keyword == "inclusiveEndDates":
+			chart.inclusive = true
+			continue
+		case keyword == "excludes":
+			chart.excludes = append(chart.excludes, parseMermaidDays(value)...)
+			continue
+		case keyword == "includes":
+			chart.includes = append(chart.includes, parseMermaidDays(value)...)
+			continue
+		case keyword == "section":
+			closeSection()
+			r.Projects = append(r.Projects, Project{Title: value})
+			section, onlyMS = len(r.Projects)-1, false
+			continue
+		case containsString(mermaidDirectives, strings.TrimSuffix(keyword, ":")):
+			continue
+		}
+
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			ds = append(ds, Diagnostic{Line: i + 1, Column: 1, Severity: SeverityWarning, Message: "unknown line, it will be ignored", Token: line})
+			continue
+		}
+
+		t, err := parseMermaidTask(strings.TrimSpace(line[:colon]), line[colon+1:], chart, lastEnd, ends)
+		if err != nil {
+			ds = append(ds, Diagnostic{Line: i + 1, Column: colon + 2, Severity: SeverityError, Message: err.Error(), Token: strings.TrimSpace(line[colon+1:])})
+			continue
+		}
+
+		if t.id != "" {
+			ends[t.id] = t.endAt
+		}
+		lastEnd = &t.endAt
+
+		if t.has("milestone") {
+			deadline := t.startAt
+			r.Milestones = append(r.Milestones, Milestone{Title: t.title, Key: t.id, DeadlineAt: &deadline})
+			onlyMS = section == len(r.Projects)-1
+			continue
+		}
+
+		p := Project{Title: t.title, Dates: chart.toDates(t), ID: t.id}
+		if section >= 0 {
+			p.Indentation = 1
+		}
+
+		// a section with a single task of the same name is a project without sub-projects, see ToMermaid
+		if section >= 0 && section == len(r.Projects)-1 && r.Projects[section].Title == t.title {
+			r.Projects = r.Projects[:section]
+			p.Indentation = 0
+		}
+
+		for _, d := range t.after {
+			if projectID[d] {
+				p.DependsOn = append(p.DependsOn, d)
+			}
+		}
+
+		switch {
+		case t.has("done"):
+			p.Percentage = 100
+			p.Status = StatusDone
+		case t.has("crit"):
+			p.Status = StatusAtRisk
+		case t.has("active"):
+			p.Status = StatusInProgress
+		}
+
+		if t.id != "" {
+			projectID[t.id] = true
+		}
+
+		r.Projects = append(r.Projects, p)
+	}
+
+	closeSection()
+
+	return r, ds
+}
+
+// mermaidTask represents a task of a Mermaid gantt chart with its dates resolved
+// endAt is the exclusive end tasks following the task start at, displayedEndAt is the exclusive end displayed, which
+// only differs if the task ends with excluded days
+type mermaidTask struct {
+	title          string
+	tags           []string
+	id             string
+	after          []string
+	startAt        time.Time
+	endAt          time.Time
+	displayedEndAt time.Time
+}
+
+// has returns true if a Mermaid task has the tag given
+func (t mermaidTask) has(tag string) bool {
+	return containsString(t.tags, tag)
+}
+
+// parseMermaidTask parses the metadata of a Mermaid task (e.g. done, t1, after t0, 3d)
+// tasks without a start start when the previous task ends, lastEnd is nil if there is no previous task
+func parseMermaidTask(title, metadata string, chart mermaidChart, lastEnd *time.Time, ends map[string]time.Time) (mermaidTask, error) {
+	var (
+		t      = mermaidTask{title: title}
+		layout = chart.layout
+	)
+
+	var parts []string
+	for _, part := range strings.Split(metadata, ",") {
+		parts = append(parts, strings.TrimSpace(part))
+	}
+
+	for len(parts) > 1 && containsString(mermaidTags, parts[0]) {
+		t.tags = append(t.tags, parts[0])
+		parts = parts[1:]
+	}
+
+	var start, end string
+
+	switch len(parts) {
+	case 1:
+		end = parts[0]
+	case 2:
+		start, end = parts[0], parts[1]
+	case 3:
+		t.id, start, end = parts[0], parts[1], parts[2]
+	default:
+		return t, errors.New("invalid task, expected format: title :tags, id, start, end")
+	}
+
+	switch {
+	case start == "":
+		if lastEnd == nil {
+			return t, errors.New("task has no start and there is no previous task to start after")
+		}
+
+		t.startAt = *lastEnd
+	case strings.HasPrefix(start, mermaidAfterPrefix):
+		t.after = strings.Fields(start[len(mermaidAfterPrefix):])
+
+		for _, ref := range t.after {
+			e, ok := ends[ref]
+			if !ok {
+				return t, fmt.Errorf("task %s not found", ref)
+			}
+
+			if e.After(t.startAt) {
+				t.startAt = e
+			}
+		}
+	default:
+		s, err := parseDate(layout, start)
+		if err != nil {
+			return t, fmt.Errorf("%w, expected format: %s", errInvalidDate, layout)
+		}
+
+		t.startAt = s
+	}
+
+	if m := mermaidDurationRegexp.FindStringSubmatch(end); m != nil {
+		n, _ := strconv.Atoi(m[1])
+
+		switch m[2] {
+		case "m":
+			t.endAt = t.startAt.Add(time.Duration(n) * time.Minute)
+		case "h":
+			t.endAt = t.startAt.Add(time.Duration(n) * time.Hour)
+		case "d":
+			t.endAt = t.startAt.AddDate(0, 0, n)
+		case "w":
+			t.endAt = t.startAt.AddDate(0, 0, n*7)
+		}
+
+		t.endAt, t.displayedEndAt = chart.skipExcluded(t.startAt, t.endAt)
+
+		return t, nil
+	}
+
+	e, err := parseDate(layout, end)
+	if err != nil {
+		return t, fmt.Errorf("%w, expected a date (%s) or a duration (e.g. 3d)", errInvalidDate, layout)
+	}
+
+	if e.Before(t.startAt) {
+		return t, errors.New("end date is before start date")
+	}
+
+	if chart.inclusive {
+		e = e.AddDate(0, 0, 1)
+	}
+
+	t.endAt, t.displayedEndAt = e, e
+
+	return t, nil
+}
@@ -0,0 +1,238 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisualRoadmap_ToMermaid(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Content
+		want string
+	}{
+		{
+			"sections, tasks, dependencies and milestones",
+			`---
+title: Launch: v1
+---
+Backend
+	API [2020-02-01, 2020-02-15, 100%, id:api]
+	Storage [2020-02-16, 2020-03-01, 50%, after:api]
+	Docs [2020-02-20, 2020-03-01, after:api]
+Marketing [2020-02-10, 2020-03-10]
+Undated
+
+|Beta [2020-03-01]
+|Later`,
+			`gantt
+    title Launch - v1
+    dateFormat YYYY-MM-DD
+    inclusiveEndDates
+    section Backend
+    API :done, t2, 2020-02-01, 2020-02-15
+    Storage :active, t3, after t2, 2020-03-01
+    Docs :t4, 2020-02-20, 2020-03-01
+    section Marketing
+    Marketing :t5, 2020-02-10, 2020-03-10
+    section Undated
+    section Milestones
+    Beta :milestone, m1, 2020-03-01, 0d
+`,
+		},
+		{
+			"time of day",
+			`Release [2020-02-01 09:00, 2020-02-01 17:30]`,
+			`gantt
+    dateFormat YYYY-MM-DD HH:mm
+    section Release
+    Release :t1, 2020-02-01 09:00, 2020-02-01 17:30
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := tt.c.Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+			assert.Equal(t, tt.want, r.ToVisual().ToMermaid())
+		})
+	}
+}
+
+func TestParseMermaid(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02 15:04", s)
+		return d
+	}
+	ptr := func(d time.Time) *time.Time {
+		return &d
+	}
+
+	type want struct {
+		title      string
+		dateFormat string
+		projects   []Project
+		milestones []Milestone
+	}
+
+	tests := []struct {
+		name       string
+		content    string
+		want       want
+		wantErrors bool
+	}{
+		{
+			"sections, tags, durations, dependencies and milestones",
+			`%% exported chart
+gantt
+    title Launch
+    dateFormat YYYY-MM-DD
+    axisFormat %m/%d
+    section Backend
+    API :done, api, 2020-02-01, 2w
+    Storage :crit, after api, 2020-03-01
+    Docs :active, docs, after api, 3d
+    Review :1d
+    section Milestones
+    Beta :milestone, beta, 2020-03-01, 0d`,
+			want{
+				title:      "Default",
+				dateFormat: "2006-01-02",
+				projects: []Project{
+					{Title: "Backend"},
+					{Title: "API", Indentation: 1, ID: "api", Percentage: 100, Status: StatusDone, Dates: &Dates{StartAt: date("2020-02-01 00:00"), EndAt: date("2020-02-14 00:00")}},
+					{Title: "Storage", Indentation: 1, Status: StatusAtRisk, DependsOn: []string{"api"}, Dates: &Dates{StartAt: date("2020-02-15 00:00"), EndAt: date("2020-02-29 00:00")}},
+					{Title: "Docs", Indentation: 1, ID: "docs", Status: StatusInProgress, DependsOn: []string{"api"}, Dates: &Dates{StartAt: date("2020-02-15 00:00"), EndAt: date("2020-02-17 00:00")}},
+					{Title: "Review", Indentation: 1, Dates: &Dates{StartAt: date("2020-02-18 00:00"), EndAt: date("2020-02-18 00:00")}},
+				},
+				milestones: []Milestone{
+					{Title: "Beta", Key: "beta", DeadlineAt: ptr(date("2020-03-01 00:00"))},
+				},
+			},
+			false,
+		},
+		{
+			"date format and time of day",
+			`gantt
+    dateFormat DD.MM.YYYY HH:mm
+    Release :r1, 01.02.2020 09:00, 8h`,
+			want{
+				title:      "Default",
+				dateFormat: "2006-01-02",
+				projects: []Project{
+					{Title: "Release", ID: "r1", Dates: &Dates{StartAt: date("2020-02-01 09:00"), EndAt: date("2020-02-01 17:00")}},
+				},
+			},
+			false,
+		},
+		{
+			"inclusive end dates",
+			`gantt
+    title Launch
+    dateFormat YYYY-MM-DD
+    inclusiveEndDates
+    API :api, 2020-02-01, 2020-02-14
+    Storage :after api, 2020-02-29
+    Docs :3d`,
+			want{
+				title:      "Default",
+				dateFormat: "2006-01-02",
+				projects: []Project{
+					{Title: "API", ID: "api", Dates: &Dates{StartAt: date("2020-02-01 00:00"), EndAt: date("2020-02-14 00:00")}},
+					{Title: "Storage", DependsOn: []string{"api"}, Dates: &Dates{StartAt: date("2020-02-15 00:00"), EndAt: date("2020-02-29 00:00")}},
+					{Title: "Docs", Dates: &Dates{StartAt: date("2020-03-01 00:00"), EndAt: date("2020-03-03 00:00")}},
+				},
+			},
+			false,
+		},
+		{
+			"excluded days",
+			`gantt
+    dateFormat YYYY-MM-DD
+    excludes weekends, 2020-02-12
+    includes 2020-02-15
+    API :api, 2020-02-03, 5d
+    Storage :after api, 5d
+    Docs :2020-02-10, 2020-02-14`,
+			want{
+				title:      "Default",
+				dateFormat: "2006-01-02",
+				projects: []Project{
+					{Title: "API", ID: "api", Dates: &Dates{StartAt: date("2020-02-03 00:00"), EndAt: date("2020-02-07 00:00")}},
+					{Title: "Storage", DependsOn: []string{"api"}, Dates: &Dates{StartAt: date("2020-02-10 00:00"), EndAt: date("2020-02-15 00:00")}},
+					{Title: "Docs", Dates: &Dates{StartAt: date("2020-02-10 00:00"), EndAt: date("2020-02-13 00:00")}},
+				},
+			},
+			false,
+		},
+		{
+			"error - unknown dependency",
+			`gantt
+    Docs :after api, 3d`,
+			want{},
+			true,
+		},
+		{
+			"error - no start",
+			`gantt
+    Docs :3d`,
+			want{},
+			true,
+		},
+		{
+			"error - invalid date",
+			`gantt
+    Docs :2020-13-45, 3d`,
+			want{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ds := ParseMermaid(tt.content, 1, nil, "Default", "", now)
+
+			assert.Equal(t, tt.wantErrors, ds.HasErrors())
+			if tt.wantErrors {
+				return
+			}
+
+			assert.Equal(t, tt.want.title, r.Title)
+			assert.Equal(t, tt.want.dateFormat, r.DateFormat)
+			assert.Equal(t, tt.want.projects, r.Projects)
+			assert.Equal(t, tt.want.milestones, r.Milestones)
+		})
+	}
+}
+
+func TestParseMermaid_RoundTrip(t *testing.T) {
+	c := Content(`Backend
+	API [2020-02-01, 2020-02-15, 100%]
+	Storage [2020-02-15, 2020-03-01]
+Marketing [2020-02-10, 2020-03-10]
+Undated
+
+|Beta [2020-03-01]`)
+
+	r, ds := c.Parse(1, nil, "Launch", "2006-01-02", "", time.Now())
+	assert.False(t, ds.HasErrors())
+
+	parsed, ds := ParseMermaid(r.ToVisual().ToMermaid(), 1, nil, "", "", time.Now())
+	assert.False(t, ds.HasErrors())
+
+	assert.Equal(t, r.ToVisual().ToMermaid(), parsed.ToVisual().ToMermaid())
+	assert.Equal(t, r.Projects[1].Dates, parsed.Projects[1].Dates)
+	assert.Equal(t, r.Projects[2].Dates, parsed.Projects[2].Dates)
+}
+
+func TestParseMermaid_title(t *testing.T) {
+	content := "gantt\n    title Chart\n    Release :2020-02-01, 1d"
+
+	r, _ := ParseMermaid(content, 1, nil, "Form", "", time.Now())
+	assert.Equal(t, "Form", r.Title)
+
+	r, _ = ParseMermaid(content, 1, nil, "", "", time.Now())
+	assert.Equal(t, "Chart", r.Title)
+}
@@ -48,3 +48,26 @@ func (_m *MockDbReadWriter) Get(c code.Code) (*Roadmap, error) {
 
 	return r0, r1
 }
+
+// GetLatest provides a mock function with given fields: c
+func (_m *MockDbReadWriter) GetLatest(c code.Code) (*Roadmap, error) {
+	ret := _m.Called(c)
+
+	var r0 *Roadmap
+	if rf, ok := ret.Get(0).(func(code.Code) *Roadmap); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Roadmap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(code.Code) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
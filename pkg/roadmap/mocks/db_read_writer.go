@@ -50,3 +50,26 @@ func (_m *DbReadWriter) Get(c code.Code) (*roadmap.Roadmap, error) {
 
 	return r0, r1
 }
+
+// GetLatest provides a mock function with given fields: c
+func (_m *DbReadWriter) GetLatest(c code.Code) (*roadmap.Roadmap, error) {
+	ret := _m.Called(c)
+
+	var r0 *roadmap.Roadmap
+	if rf, ok := ret.Get(0).(func(code.Code) *roadmap.Roadmap); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*roadmap.Roadmap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(code.Code) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
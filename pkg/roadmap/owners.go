@@ -0,0 +1,249 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ownerPrefix starts the handle of an owner of a project (e.g. @peter)
+const ownerPrefix = "@"
+
+// unassignedLane is the title of the swimlane collecting projects without an owner
+const unassignedLane = "Unassigned"
+
+// laneHeader is the origin of the rows displaying the title of a swimlane, see VisualRoadmap.origins
+const laneHeader = -1
+
+var errCannotParseOwner = errors.New("can not parse string as owner, expected format: @handle")
+
+// parseOwner tries to parse a string as the handle of an owner (e.g. @peter)
+func parseOwner(part string) (string, error) {
+	handle := part[len(ownerPrefix):]
+	if handle == "" || strings.ContainsAny(handle, " \t") {
+		return "", errCannotParseOwner
+	}
+
+	return handle, nil
+}
+
+// Layout represents the way projects are arranged on a roadmap image
+type Layout string
+
+const (
+	DefaultLayout   Layout = "default"
+	SwimlanesLayout Layout = "swimlanes"
+)
+
+// NewLayout converts a string into a Layout, an empty string results in the default layout
+func NewLayout(l string) (Layout, error) {
+	switch l {
+	case "", "default":
+		return DefaultLayout, nil
+	case "swimlanes":
+		return SwimlanesLayout, nil
+	}
+
+	return "", fmt.Errorf("unsupported layout: %s", l)
+}
+
+// ApplyLayout rearranges the projects of a visual roadmap based on the layout given
+func (vr *VisualRoadmap) ApplyLayout(layout Layout) *VisualRoadmap {
+	if layout == SwimlanesLayout {
+		return vr.toSwimlanes()
+	}
+
+	return vr
+}
+
+// toSwimlanes groups the projects of a visual roadmap by their owners
+// projects without owners inherit the owners of their closest parent having any, projects with multiple owners appear
+// in multiple swimlanes, projects without any owner end up in a swimlane called "Unassigned"
+// the hierarchy of projects is kept within swimlanes as far as possible
+// the project each row was created from is kept track of, so that rows can be mapped back to projects, see origin
+func (vr *VisualRoadmap) toSwimlanes() *VisualRoadmap {
+	owners := effectiveOwners(vr.Projects)
+
+	var lanes []string
+	seen := map[string]bool{}
+	for _, po := range owners {
+		for _, o := range po {
+			if !seen[o] {
+				seen[o] = true
+				lanes = append(lanes, o)
+			}
+		}
+	}
+
+	var (
+		projects []Project
+		origins  []int
+	)
+	for _, lane := range lanes {
+		lp, lo := laneProjects(vr.Projects, owners, lane)
+		projects = append(projects, Project{Title: ownerPrefix + lane})
+		projects = append(projects, lp...)
+		origins = append(origins, laneHeader)
+		origins = append(origins, lo...)
+	}
+
+	unassigned, uo := laneProjects(vr.Projects, owners, "")
+	if len(unassigned) > 0 {
+		projects = append(projects, Project{Title: unassignedLane})
+		projects = append(projects, unassigned...)
+		origins = append(origins, laneHeader)
+		origins = append(origins, uo...)
+	}
+
+	lanesVR := &VisualRoadmap{
+		Title:            vr.Title,
+		Projects:         projects,
+		Milestones:       vr.Milestones,
+		Dates:            vr.Dates,
+		DateFormat:       vr.DateFormat,
+		Progress:         vr.Progress,
+		TitleTemplate:    vr.TitleTemplate,
+		Timezone:         vr.Timezone,
+		Theme:            vr.Theme,
+		Code:             vr.Code,
+		CalendarProjects: vr.CalendarProjects,
+		origins:          origins,
+		dependencies:     laneDependencies(vr.dependencies, origins),
+	}
+
+	return lanesVR.calculateProjectDates().calculateProjectColors().calculatePercentages().calculateEstimates()
+}
+
+// laneDependencies maps the dependencies of projects to the rows of swimlanes
+// projects appearing in multiple swimlanes depend on the rows in their own swimlane if possible
+func laneDependencies(dependencies [][]int, origins []int) [][]int {
+	if dependencies == nil {
+		return nil
+	}
+
+	var (
+		result = make([][]int, len(origins))
+		lanes  = make([]int, len(origins))
+		rows   = map[int][]int{}
+		lane   = -1
+	)
+
+	for row, o := range origins {
+		if o == laneHeader {
+			lane++
+		}
+
+		lanes[row] = lane
+		rows[o] = append(rows[o], row)
+	}
+
+	for row, o := range origins {
+		if o == laneHeader {
+			continue
+		}
+
+		for _, d := range dependencies[o] {
+			candidates := rows[d]
+			if len(candidates) == 0 {
+				continue
+			}
+
+			target := candidates[0]
+			for _, c := range candidates {
+				if lanes[c] == lanes[row] {
+					target = c
+					break
+				}
+			}
+
+			result[row] = append(result[row], target)
+		}
+	}
+
+	return result
+}
+
+// effectiveOwners returns the owners of each project, projects without owners inherit the owners of their parents
+func effectiveOwners(projects []Project) [][]string {
+	var (
+		result = make([][]string, len(projects))
+		stack  []int
+	)
+
+	for i, p := range projects {
+		for len(stack) > 0 && projects[stack[len(stack)-1]].Indentation >= p.Indentation {
+			stack = stack[:len(stack)-1]
+		}
+
+		result[i] = p.Owners
+		if len(result[i]) == 0 && len(stack) > 0 {
+			result[i] = result[stack[len(stack)-1]]
+		}
+
+		stack = append(stack, i)
+	}
+
+	return result
+}
+
+// laneProjects collects the projects belonging to a swimlane, an empty owner collects projects without owners
+// indentation is recalculated so that projects are nested below their closest parent within the same swimlane
+// the index of the original project is returned for each project collected
+func laneProjects(projects []Project, owners [][]string, owner string) ([]Project, []int) {
+	var (
+		result  []Project
+		origins []int
+		stack   []uint8
+	)
+
+	for i, p := range projects {
+		if !isInLane(owners[i], owner) {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1] >= p.Indentation {
+			stack = stack[:len(stack)-1]
+		}
+
+		p.Indentation = uint8(len(stack)) + 1
+		p.Owners = nil
+		result = append(result, p)
+		origins = append(origins, i)
+
+		stack = append(stack, projects[i].Indentation)
+	}
+
+	return result, origins
+}
+
+// isInLane returns true if a project with the given owners belongs to the swimlane of an owner
+func isInLane(owners []string, owner string) bool {
+	if owner == "" {
+		return len(owners) == 0
+	}
+
+	for _, o := range owners {
+		if o == owner {
+			return true
+		}
+	}
+
+	return false
+}
+
+// origin returns the index of the project a row of the visual roadmap was created from and whether the row is the
+// first one created from that project, lane headers are not created from any project
+func (vr *VisualRoadmap) origin(row int, seen map[int]bool) (int, bool) {
+	if vr.origins == nil {
+		return row, true
+	}
+
+	o := vr.origins[row]
+	if o == laneHeader || seen[o] {
+		return o, false
+	}
+
+	seen[o] = true
+
+	return o, true
+}
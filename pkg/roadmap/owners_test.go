@@ -0,0 +1,136 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    string
+		wantErr error
+	}{
+		{"handle", "@peter", "peter", nil},
+		{"empty handle", "@", "", errCannotParseOwner},
+		{"handle with whitespace", "@peter aba", "", errCannotParseOwner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOwner(tt.part)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		l       string
+		want    Layout
+		wantErr bool
+	}{
+		{"empty", "", DefaultLayout, false},
+		{"default", "default", DefaultLayout, false},
+		{"swimlanes", "swimlanes", SwimlanesLayout, false},
+		{"unknown", "gantt", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewLayout(tt.l)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVisualRoadmap_ApplyLayout(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC)
+		d3 = time.Date(2020, 2, 20, 0, 0, 0, 0, time.UTC)
+	)
+
+	projects := []Project{
+		{Title: "Backend", Owners: []string{"peter"}},
+		{Title: "Create API", Indentation: 1, Dates: &Dates{StartAt: d1, EndAt: d2}, Percentage: 40},
+		{Title: "Create DB", Indentation: 1, Dates: &Dates{StartAt: d2, EndAt: d3}, Percentage: 60, Owners: []string{"anna"}},
+		{Title: "Marketing", Dates: &Dates{StartAt: d1, EndAt: d3}, Percentage: 20},
+	}
+
+	tests := []struct {
+		name   string
+		layout Layout
+		want   []Project
+	}{
+		{
+			"default layout keeps projects",
+			DefaultLayout,
+			projects,
+		},
+		{
+			"swimlanes group projects by owners",
+			SwimlanesLayout,
+			[]Project{
+				{Title: "@peter", Dates: &Dates{StartAt: d1, EndAt: d3}, Percentage: 50},
+				{Title: "Backend", Indentation: 1, Dates: &Dates{StartAt: d1, EndAt: d3}, Percentage: 50},
+				{Title: "Create API", Indentation: 2, Dates: &Dates{StartAt: d1, EndAt: d2}, Percentage: 40},
+				{Title: "@anna", Dates: &Dates{StartAt: d2, EndAt: d3}, Percentage: 60},
+				{Title: "Create DB", Indentation: 1, Dates: &Dates{StartAt: d2, EndAt: d3}, Percentage: 60},
+				{Title: "Unassigned", Dates: &Dates{StartAt: d1, EndAt: d3}, Percentage: 20},
+				{Title: "Marketing", Indentation: 1, Dates: &Dates{StartAt: d1, EndAt: d3}, Percentage: 20},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &VisualRoadmap{Projects: append([]Project{}, projects...)}
+			vr.calculateProjectDates().calculatePercentages()
+
+			got := vr.ApplyLayout(tt.layout)
+
+			for i := range got.Projects {
+				got.Projects[i].Color = nil
+			}
+
+			if tt.layout == DefaultLayout {
+				assert.Equal(t, len(tt.want), len(got.Projects))
+				return
+			}
+
+			assert.Equal(t, tt.want, got.Projects)
+		})
+	}
+}
+
+func TestVisualRoadmap_ApplyLayout_dependencies(t *testing.T) {
+	c := Content(`Backend [@peter, @anna]
+	API [2020-02-01, 2020-02-10, id:api]
+	Storage [2020-02-10, 2020-02-20, after:api]
+Docs [2020-02-10, 2020-02-15, @anna, after:api, ~docs]
+Marketing [2020-02-01, 2020-02-15, after:Docs, ~docs]`)
+
+	r, ds := c.Parse(1, nil, "", "", "", time.Now())
+	require.False(t, ds.HasErrors(), ds.Error())
+
+	t.Run("swimlanes", func(t *testing.T) {
+		got := r.ToVisual().ApplyLayout(SwimlanesLayout)
+
+		// @peter: Backend, API, Storage; @anna: Backend, API, Storage, Docs; Unassigned: Marketing
+		assert.Equal(t, [][]int{nil, nil, nil, {2}, nil, nil, nil, {6}, {6}, nil, {8}}, got.dependencies)
+
+		got.Draw(800, 20, false, time.Now())
+	})
+
+	t.Run("filtered swimlanes", func(t *testing.T) {
+		got := r.ToVisual().FilterByLabels([]string{"docs"}, nil).ApplyLayout(SwimlanesLayout)
+
+		// @anna: Docs; Unassigned: Marketing
+		assert.Equal(t, [][]int{nil, nil, nil, {1}}, got.dependencies)
+	})
+}
@@ -0,0 +1,77 @@
+package roadmap
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// periodRegexp matches quarters (e.g. 2021-Q3), ISO weeks (e.g. 2021-W14) and months (e.g. 2021-03)
+var periodRegexp = regexp.MustCompile(`^(\d{4})-([QW])?(\d{1,2})$`)
+
+var errInvalidPeriod = errors.New("invalid period, expected format: 2021-Q3, 2021-W14 or 2021-03")
+
+// isPeriod returns true if a string looks like a quarter, an ISO week or a month
+func isPeriod(part string) bool {
+	return periodRegexp.MatchString(part)
+}
+
+// parsePeriod returns the first and the last day of a quarter (e.g. 2021-Q3), an ISO week (e.g. 2021-W14) or a month
+// (e.g. 2021-03)
+func parsePeriod(part string) (Dates, error) {
+	m := periodRegexp.FindStringSubmatch(part)
+	if m == nil {
+		return Dates{}, errInvalidPeriod
+	}
+
+	year, err := strconv.Atoi(m[1])
+	if err != nil {
+		return Dates{}, errInvalidPeriod
+	}
+
+	n, err := strconv.Atoi(m[3])
+	if err != nil {
+		return Dates{}, errInvalidPeriod
+	}
+
+	switch m[2] {
+	case "Q":
+		if n < 1 || n > 4 {
+			return Dates{}, errInvalidPeriod
+		}
+
+		startAt := time.Date(year, time.Month((n-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+
+		return Dates{StartAt: startAt, EndAt: startAt.AddDate(0, 3, -1)}, nil
+	case "W":
+		// January 4th is always in the first ISO week of the year
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+		startAt := jan4.AddDate(0, 0, -(int(jan4.Weekday())+6)%7+(n-1)*7)
+
+		if y, w := startAt.ISOWeek(); y != year || w != n {
+			return Dates{}, errInvalidPeriod
+		}
+
+		return Dates{StartAt: startAt, EndAt: startAt.AddDate(0, 0, 6)}, nil
+	}
+
+	if n < 1 || n > 12 {
+		return Dates{}, errInvalidPeriod
+	}
+
+	startAt := time.Date(year, time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+
+	return Dates{StartAt: startAt, EndAt: startAt.AddDate(0, 1, -1)}, nil
+}
+
+// parseDateOrPeriod parses a string either as a date using the date format given or as a period
+// dates are returned as periods starting and ending on the same day
+func parseDateOrPeriod(part, dateFormat string) (Dates, error) {
+	t, err := parseDate(dateFormat, part)
+	if err == nil {
+		return Dates{StartAt: t, EndAt: t}, nil
+	}
+
+	return parsePeriod(part)
+}
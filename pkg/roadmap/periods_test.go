@@ -0,0 +1,44 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePeriod(t *testing.T) {
+	d := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		part    string
+		want    Dates
+		wantErr bool
+	}{
+		{"quarter", "2021-Q3", Dates{StartAt: d(2021, 7, 1), EndAt: d(2021, 9, 30)}, false},
+		{"last quarter", "2021-Q4", Dates{StartAt: d(2021, 10, 1), EndAt: d(2021, 12, 31)}, false},
+		{"invalid quarter", "2021-Q5", Dates{}, true},
+		{"week", "2021-W14", Dates{StartAt: d(2021, 4, 5), EndAt: d(2021, 4, 11)}, false},
+		{"first week starting in the previous year", "2020-W01", Dates{StartAt: d(2019, 12, 30), EndAt: d(2020, 1, 5)}, false},
+		{"week 53", "2020-W53", Dates{StartAt: d(2020, 12, 28), EndAt: d(2021, 1, 3)}, false},
+		{"invalid week 53", "2021-W53", Dates{}, true},
+		{"month", "2021-03", Dates{StartAt: d(2021, 3, 1), EndAt: d(2021, 3, 31)}, false},
+		{"leap month", "2020-2", Dates{StartAt: d(2020, 2, 1), EndAt: d(2020, 2, 29)}, false},
+		{"invalid month", "2021-13", Dates{}, true},
+		{"date", "2021-03-01", Dates{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePeriod(tt.part)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
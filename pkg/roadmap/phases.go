@@ -0,0 +1,199 @@
+package roadmap
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tdewolff/canvas"
+)
+
+// phaseSeparator separates the start and the end of a phase (e.g. 2020-02-01..2020-02-14)
+const phaseSeparator = ".."
+
+// phaseTitleSeparator separates the optional title of a phase from its dates (e.g. Design:2020-02-01..2020-02-14)
+const phaseTitleSeparator = ":"
+
+// phaseRegexp matches phases, with an optional title
+// dates of phases may only contain colons as part of their time of day, therefore the title lasts until the last colon
+// followed by a date
+var phaseRegexp = regexp.MustCompile(`^(?:(.*):)?\s*(` + phaseDatePattern + `)\s*\.\.\s*(` + phaseDatePattern + `)\s*$`)
+
+// phaseDatePattern matches the dates of phases: a string containing a date separator, optionally followed by a time of
+// day
+const phaseDatePattern = `[^\s:]*?[-./][^\s:]*?(?: \d{1,2}:\d{2})?`
+
+var errInvalidPhase = errors.New("invalid phase, expected format: 2020-02-01..2020-02-14 or Design:2020-02-01..2020-02-14")
+
+// Phase represents a named or unnamed date range of a project, projects with multiple phases are displayed as
+// segmented bars
+type Phase struct {
+	Title string `json:"title,omitempty"`
+	Dates Dates  `json:"dates"`
+}
+
+// String converts a Phase into a string
+func (ph Phase) String(dateFormat string) string {
+	dates := formatDate(ph.Dates.StartAt, dateFormat) + phaseSeparator + formatDate(ph.Dates.EndAt, dateFormat)
+
+	if ph.Title == "" {
+		return dates
+	}
+
+	return ph.Title + phaseTitleSeparator + dates
+}
+
+// isPhase returns true if a string looks like a phase, meaning that it contains two date-like strings separated by ..
+func isPhase(part, dateFormat string) bool {
+	m := phaseRegexp.FindStringSubmatch(part)
+	if m == nil {
+		return false
+	}
+
+	return isDateOrDateLike(m[2], dateFormat) && isDateOrDateLike(m[3], dateFormat)
+}
+
+// isDateOrDateLike returns true if a string is a date or at least looks like one
+func isDateOrDateLike(s, dateFormat string) bool {
+	if isDateLike(s) {
+		return true
+	}
+
+	_, err := parseDate(dateFormat, s)
+
+	return err == nil
+}
+
+// parsePhase tries to parse a string as a phase (e.g. Design:2020-02-01..2020-02-14)
+func parsePhase(part, dateFormat string) (Phase, error) {
+	m := phaseRegexp.FindStringSubmatch(part)
+	if m == nil {
+		return Phase{}, errInvalidPhase
+	}
+
+	startAt, err := parseDate(dateFormat, strings.TrimSpace(m[2]))
+	if err != nil {
+		return Phase{}, errInvalidPhase
+	}
+
+	endAt, err := parseDate(dateFormat, strings.TrimSpace(m[3]))
+	if err != nil {
+		return Phase{}, errInvalidPhase
+	}
+
+	if endAt.Before(startAt) {
+		return Phase{}, errors.New("end date of phase is before its start date")
+	}
+
+	return Phase{Title: strings.TrimSpace(m[1]), Dates: Dates{StartAt: startAt, EndAt: endAt}}, nil
+}
+
+// phasesSpan returns the dates covering all phases given, nil if there are no phases
+func phasesSpan(phases []Phase) *Dates {
+	var d *Dates
+
+	for _, ph := range phases {
+		d = extendDates(d, ph.Dates)
+	}
+
+	return d
+}
+
+// span returns the dates covering both the dates and the phases of a project, nil if neither are set
+func (p Project) span() *Dates {
+	d := phasesSpan(p.Phases)
+
+	if p.Dates != nil {
+		d = extendDates(d, *p.Dates)
+	}
+
+	return d
+}
+
+// extendDates returns a copy of d extended to also cover e, d may be nil
+func extendDates(d *Dates, e Dates) *Dates {
+	if d == nil {
+		return &Dates{StartAt: e.StartAt, EndAt: e.EndAt}
+	}
+
+	result := *d
+
+	if e.StartAt.Before(result.StartAt) {
+		result.StartAt = e.StartAt
+	}
+
+	if e.EndAt.After(result.EndAt) {
+		result.EndAt = e.EndAt
+	}
+
+	return &result
+}
+
+// hasDerivedDates returns true if the dates of a project are only the result of its phases, in which case they
+// don't need to be written out
+func (p Project) hasDerivedDates() bool {
+	span := phasesSpan(p.Phases)
+
+	return span != nil && p.Dates != nil && span.StartAt.Equal(p.Dates.StartAt) && span.EndAt.Equal(p.Dates.EndAt)
+}
+
+// drawPhases draws the bar of a project with phases as segments connected by a thin line
+// the percentage of a project is distributed over its phases in order
+func (vr *VisualRoadmap) drawPhases(ctx *canvas.Context, p Project, x, y, w, h, r float64) {
+	toX := func(t time.Time) float64 {
+		if !p.Dates.EndAt.After(p.Dates.StartAt) {
+			return x
+		}
+
+		return x + t.Sub(p.Dates.StartAt).Hours()/p.Dates.EndAt.Sub(p.Dates.StartAt).Hours()*w
+	}
+
+	connector := &canvas.Path{}
+	connector.MoveTo(0, 0)
+	connector.LineTo(w, 0)
+	ctx.DrawPath(x, y+h/2, connector)
+
+	var total float64
+	for _, ph := range p.Phases {
+		total += ph.Dates.EndAt.Sub(ph.Dates.StartAt).Hours()
+	}
+
+	done := total * float64(p.Percentage) / 100
+
+	for _, ph := range p.Phases {
+		x0, x1 := toX(ph.Dates.StartAt), toX(ph.Dates.EndAt)
+		segmentW := x1 - x0
+
+		ctx.SetFillColor(myLightGrey)
+		ctx.DrawPath(x0, y, canvas.RoundedRectangle(segmentW, h, r))
+
+		hours := ph.Dates.EndAt.Sub(ph.Dates.StartAt).Hours()
+		if done > 0 && hours > 0 {
+			ratio := done / hours
+			if ratio > 1 {
+				ratio = 1
+			}
+
+			ctx.SetFillColor(p.Color)
+			ctx.DrawPath(x0, y, canvas.RoundedRectangle(segmentW*ratio, h, r))
+
+			done -= hours
+		}
+
+		if p.Status == StatusBlocked {
+			vr.drawHatching(ctx, x0, y, segmentW, h)
+		}
+
+		if ph.Title == "" {
+			continue
+		}
+
+		face := fontFamily.Face(h*1.2, canvas.Black, canvas.FontRegular, canvas.FontNormal)
+		if face.TextWidth(ph.Title) > segmentW {
+			continue
+		}
+
+		ctx.DrawText(x0, y+h, canvas.NewTextBox(face, ph.Title, segmentW, h, canvas.Center, canvas.Center, 0.0, 0.0))
+	}
+}
@@ -0,0 +1,89 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parsePhase(t *testing.T) {
+	var (
+		startAt = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		endAt   = time.Date(2020, 2, 14, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name    string
+		part    string
+		want    Phase
+		wantErr bool
+	}{
+		{"unnamed", "2020-02-01..2020-02-14", Phase{Dates: Dates{StartAt: startAt, EndAt: endAt}}, false},
+		{"named", "Design:2020-02-01..2020-02-14", Phase{Title: "Design", Dates: Dates{StartAt: startAt, EndAt: endAt}}, false},
+		{"name with colon", "Step 1: design:2020-02-01..2020-02-14", Phase{Title: "Step 1: design", Dates: Dates{StartAt: startAt, EndAt: endAt}}, false},
+		{"invalid date", "Design:2020-02-01..2020-02-31", Phase{}, true},
+		{"end before start", "2020-02-14..2020-02-01", Phase{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, isPhase(tt.part, "2006-01-02"))
+
+			got, err := parsePhase(tt.part, "2006-01-02")
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_isPhase(t *testing.T) {
+	assert.False(t, isPhase("2020-02-01", "2006-01-02"))
+	assert.False(t, isPhase("https://example.com/a..b", "2006-01-02"))
+	assert.False(t, isPhase("2021-Q3..2021-Q4", "2006-01-02"))
+}
+
+func TestContent_Parse_phases(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 8, 0, 0, 0, 0, time.UTC)
+		d3 = time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+		d4 = time.Date(2020, 2, 25, 0, 0, 0, 0, time.UTC)
+	)
+
+	const content = `Create API [Design:2020-02-01..2020-02-08, Build:2020-02-12..2020-02-25, 60%]
+Create DB [2020-02-01, 2020-03-01, 2020-02-12..2020-02-25]`
+
+	r, ds := Content(content).Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Equal(t, &Dates{StartAt: d1, EndAt: d4}, r.Projects[0].Dates)
+	assert.Equal(t, []Phase{{Title: "Design", Dates: Dates{StartAt: d1, EndAt: d2}}, {Title: "Build", Dates: Dates{StartAt: d3, EndAt: d4}}}, r.Projects[0].Phases)
+	assert.Equal(t, &Dates{StartAt: d1, EndAt: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}, r.Projects[1].Dates)
+
+	assert.Equal(t, content, r.String())
+}
+
+func TestVisualRoadmap_findDatesBottomUp_phases(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 8, 0, 0, 0, 0, time.UTC)
+		d3 = time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+		d4 = time.Date(2020, 2, 25, 0, 0, 0, 0, time.UTC)
+	)
+
+	r := Roadmap{
+		Projects: []Project{
+			{Title: "Backend"},
+			{Title: "Create API", Indentation: 1, Dates: &Dates{StartAt: d2, EndAt: d3}, Phases: []Phase{{Dates: Dates{StartAt: d1, EndAt: d2}}}},
+			{Title: "Create DB", Indentation: 1, Phases: []Phase{{Dates: Dates{StartAt: d3, EndAt: d4}}}},
+		},
+	}
+
+	assert.Equal(t, &Dates{StartAt: d1, EndAt: d4}, r.ToDates())
+
+	vr := r.ToVisual()
+
+	assert.Equal(t, &Dates{StartAt: d1, EndAt: d4}, vr.Projects[0].Dates)
+	assert.Equal(t, &Dates{StartAt: d1, EndAt: d3}, vr.Projects[1].Dates)
+	assert.Equal(t, &Dates{StartAt: d3, EndAt: d4}, vr.Projects[2].Dates)
+}
@@ -0,0 +1,79 @@
+package roadmap
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ProgressStrategy represents the way percentages of projects are calculated from the percentages of their
+// sub-projects
+type ProgressStrategy string
+
+const (
+	// ProgressAverage gives the same weight to all sub-projects, this is the default
+	ProgressAverage ProgressStrategy = "average"
+	// ProgressDuration weights sub-projects by the time between their start and end dates
+	ProgressDuration ProgressStrategy = "duration"
+	// ProgressWeight weights sub-projects by their weight (e.g. weight=5), sub-projects without a weight count as 1
+	ProgressWeight ProgressStrategy = "weight"
+)
+
+var errUnknownProgressStrategy = errors.New("unknown progress strategy, expected one of: average, duration, weight")
+
+// parseProgressStrategy tries to parse a string as a progress strategy, the case is ignored
+func parseProgressStrategy(s string) (ProgressStrategy, error) {
+	switch ps := ProgressStrategy(strings.ToLower(s)); ps {
+	case ProgressAverage, ProgressDuration, ProgressWeight:
+		return ps, nil
+	}
+
+	return "", errUnknownProgressStrategy
+}
+
+// weightPrefix starts the weight of a project used for calculating the percentage of its parent (e.g. weight=5)
+const weightPrefix = "weight="
+
+var (
+	errCannotParseWeight = errors.New("can not parse string as weight, expected a positive number (e.g. weight=5)")
+	errReservedWeightKey = errors.New("weight is reserved for the weight of projects and can not be used as a field key, expected a positive number (e.g. weight=5)")
+)
+
+// parseWeight tries to parse a string as the weight of a project (e.g. weight=5, weight=0.5)
+// weight can not be used as the key of a custom field, non-numeric values result in an error explaining this
+func parseWeight(part string) (float64, error) {
+	w, err := strconv.ParseFloat(part[len(weightPrefix):], 64)
+	if err != nil {
+		return 0, errReservedWeightKey
+	}
+
+	if w <= 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+		return 0, errCannotParseWeight
+	}
+
+	return w, nil
+}
+
+// formatWeight converts a weight into a string usable in the extra part of a project
+func formatWeight(w float64) string {
+	return weightPrefix + strconv.FormatFloat(w, 'f', -1, 64)
+}
+
+// progressWeight returns the weight of a sub-project when calculating the percentage of its parent
+func (vr *VisualRoadmap) progressWeight(p Project) float64 {
+	switch vr.Progress {
+	case ProgressDuration:
+		if p.Dates == nil {
+			return 0
+		}
+
+		return p.Dates.EndAt.Sub(p.Dates.StartAt).Hours()
+	case ProgressWeight:
+		if p.Weight > 0 {
+			return p.Weight
+		}
+	}
+
+	return 1
+}
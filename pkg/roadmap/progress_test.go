@@ -0,0 +1,136 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseProgressStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    ProgressStrategy
+		wantErr error
+	}{
+		{"average", "average", ProgressAverage, nil},
+		{"case is ignored", "Weight", ProgressWeight, nil},
+		{"unknown", "median", "", errUnknownProgressStrategy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseProgressStrategy(tt.s)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    float64
+		wantErr error
+	}{
+		{"integer", "weight=5", 5, nil},
+		{"fraction", "weight=0.5", 0.5, nil},
+		{"zero", "weight=0", 0, errCannotParseWeight},
+		{"not a number", "weight=NaN", 0, errCannotParseWeight},
+		{"infinite", "weight=Inf", 0, errCannotParseWeight},
+		{"reserved key", "weight=high", 0, errReservedWeightKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseWeight(tt.part)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVisualRoadmap_findPercentageBottomUp_strategies(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 4, 0, 0, 0, 0, time.UTC)
+		d3 = time.Date(2020, 2, 5, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name     string
+		progress ProgressStrategy
+		projects []Project
+		want     uint8
+	}{
+		{
+			"many finished sub-projects do not overflow",
+			ProgressAverage,
+			[]Project{
+				{Title: "parent"},
+				{Title: "a", Indentation: 1, Percentage: 100},
+				{Title: "b", Indentation: 1, Percentage: 100},
+				{Title: "c", Indentation: 1, Percentage: 100},
+			},
+			100,
+		},
+		{
+			"average",
+			ProgressAverage,
+			[]Project{
+				{Title: "parent"},
+				{Title: "a", Indentation: 1, Percentage: 100, Dates: &Dates{StartAt: d1, EndAt: d2}, Weight: 3},
+				{Title: "b", Indentation: 1, Percentage: 20, Dates: &Dates{StartAt: d2, EndAt: d3}},
+			},
+			60,
+		},
+		{
+			"duration",
+			ProgressDuration,
+			[]Project{
+				{Title: "parent"},
+				{Title: "a", Indentation: 1, Percentage: 100, Dates: &Dates{StartAt: d1, EndAt: d2}},
+				{Title: "b", Indentation: 1, Percentage: 20, Dates: &Dates{StartAt: d2, EndAt: d3}},
+			},
+			80,
+		},
+		{
+			"duration without dates",
+			ProgressDuration,
+			[]Project{
+				{Title: "parent"},
+				{Title: "a", Indentation: 1, Percentage: 100},
+				{Title: "b", Indentation: 1, Percentage: 20},
+			},
+			60,
+		},
+		{
+			"weight",
+			ProgressWeight,
+			[]Project{
+				{Title: "parent"},
+				{Title: "a", Indentation: 1, Percentage: 100, Weight: 3},
+				{Title: "b", Indentation: 1, Percentage: 20},
+			},
+			80,
+		},
+		{
+			"nested",
+			ProgressWeight,
+			[]Project{
+				{Title: "parent"},
+				{Title: "a", Indentation: 1, Weight: 3},
+				{Title: "a1", Indentation: 2, Percentage: 100},
+				{Title: "b", Indentation: 1, Percentage: 20},
+			},
+			80,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &VisualRoadmap{Projects: tt.projects, Progress: tt.progress}
+
+			assert.Equal(t, tt.want, vr.findPercentageBottomUp(0))
+		})
+	}
+}
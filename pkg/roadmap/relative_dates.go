@@ -0,0 +1,290 @@
+package roadmap
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationRegexp matches durations in days or weeks (e.g. 10d, 3w)
+var durationRegexp = regexp.MustCompile(`^(\d+)([dw])$`)
+
+// relativeDateRegexp matches dates relative to the parent (e.g. ^, ^+1w) or to the previous sibling (e.g. +2w, -3d)
+var relativeDateRegexp = regexp.MustCompile(`^(\^)?(([+-])(\d+)([dw]))?$`)
+
+var errNotDuration = errors.New("not a duration")
+
+// isDuration returns true if a string represents a duration (e.g. 10d, 3w)
+func isDuration(part string) bool {
+	return durationRegexp.MatchString(part)
+}
+
+// isRelativeDate returns true if a string represents a relative start date (e.g. ^, ^+1w, +2w, -3d)
+func isRelativeDate(part string) bool {
+	return part != "" && relativeDateRegexp.MatchString(part)
+}
+
+// parseDays converts a number and a unit (d or w) into a number of days
+func parseDays(n, unit string) (int, error) {
+	days, err := strconv.Atoi(n)
+	if err != nil {
+		return 0, err
+	}
+
+	if unit == "w" {
+		days *= 7
+	}
+
+	return days, nil
+}
+
+// parseDuration returns the number of days a duration represents
+func parseDuration(part string) (int, error) {
+	m := durationRegexp.FindStringSubmatch(part)
+	if m == nil {
+		return 0, errNotDuration
+	}
+
+	return parseDays(m[1], m[2])
+}
+
+// hasDateExpressions returns true if the dates of a project are (partially) defined via expressions (relative dates,
+// durations or periods)
+func (p Project) hasDateExpressions() bool {
+	return p.StartExpression != "" || p.EndExpression != ""
+}
+
+// toExpressions returns the start and end expressions of a project if any relative dates, durations or periods are used
+// absolute dates are formatted using the date format, so that they can be used with expressions
+func (e extraData) toExpressions(dateFormat string) (string, string) {
+	if e.startExpr == "" && e.endExpr == "" {
+		return "", ""
+	}
+
+	startExpression, endExpression := e.startExpr, e.endExpr
+
+	if e.startAt != nil {
+		startExpression = formatDate(*e.startAt, dateFormat)
+	}
+
+	if e.endAt != nil {
+		endExpression = formatDate(*e.endAt, dateFormat)
+	}
+
+	return startExpression, endExpression
+}
+
+// resolveRelativeDates calculates the dates of projects using relative dates or durations and returns diagnostics
+// about the projects which dates could not be calculated
+func (r Roadmap) resolveRelativeDates(loc locator) Diagnostics {
+	var ds Diagnostics
+
+	for _, i := range resolveDateExpressions(r.Projects, r.DateFormat) {
+		p := r.Projects[i]
+
+		ds = append(ds, loc.project(i, SeverityWarning, "dates could not be calculated, a start and an end are needed and relative dates need a parent, a previous sibling or a dependency with dates", strings.Trim(p.StartExpression+", "+p.EndExpression, ", ")))
+	}
+
+	for i, p := range r.Projects {
+		if !p.hasDateExpressions() || p.Dates == nil || !p.Dates.EndAt.Before(p.Dates.StartAt) {
+			continue
+		}
+
+		ds = append(ds, loc.project(i, SeverityError, "end date is before start date", strings.Trim(p.StartExpression+", "+p.EndExpression, ", ")))
+	}
+
+	return ds
+}
+
+// resolveDateExpressions calculates the dates of projects which have start or end expressions (e.g. +2w, ^, 10d)
+// projects are resolved repeatedly, so that projects can depend on projects which are resolved later
+// the indexes of the projects which could not be resolved are returned
+func resolveDateExpressions(projects []Project, dateFormat string) []int {
+	var (
+		resolved = make([]bool, len(projects))
+		changed  = true
+	)
+
+	for i, p := range projects {
+		resolved[i] = !p.hasDateExpressions()
+	}
+
+	for changed {
+		changed = false
+
+		for i := range projects {
+			if resolved[i] {
+				continue
+			}
+
+			dates := resolveProjectDates(projects, i, resolved, dateFormat)
+			if dates == nil {
+				continue
+			}
+
+			projects[i].Dates = dates
+			resolved[i] = true
+			changed = true
+		}
+	}
+
+	var unresolved []int
+	for i := range resolved {
+		if !resolved[i] {
+			unresolved = append(unresolved, i)
+		}
+	}
+
+	return unresolved
+}
+
+// resolveProjectDates tries to calculate the dates of a project using the start and end expressions of it
+// nil is returned if a project needed as an anchor is not resolved yet
+func resolveProjectDates(projects []Project, i int, resolved []bool, dateFormat string) *Dates {
+	p := projects[i]
+
+	startAt, ok := resolveStart(projects, i, resolved, dateFormat)
+	if !ok {
+		return nil
+	}
+
+	if p.EndExpression == "" {
+		// a period used as the only date defines both the start and the end
+		if !isPeriod(p.StartExpression) {
+			return nil
+		}
+
+		d, err := parsePeriod(p.StartExpression)
+		if err != nil {
+			return nil
+		}
+
+		return &Dates{StartAt: startAt, EndAt: d.EndAt}
+	}
+
+	if isDuration(p.EndExpression) {
+		days, err := parseDuration(p.EndExpression)
+		if err != nil {
+			return nil
+		}
+
+		// end dates are inclusive, a project of 10 days starting on the 1st ends on the 10th
+		return &Dates{StartAt: startAt, EndAt: startAt.AddDate(0, 0, days-1)}
+	}
+
+	d, err := parseDateOrPeriod(p.EndExpression, dateFormat)
+	if err != nil {
+		return nil
+	}
+
+	return &Dates{StartAt: startAt, EndAt: d.EndAt}
+}
+
+// resolveStart calculates the start date of a project
+// relative start dates are calculated from the day after the latest end of the dependencies of the project if there are
+// any, otherwise from the day after the end of the previous sibling or the start of the parent if there's no previous
+// sibling
+// start dates starting with ^ are always calculated from the start of the parent
+// projects with dependencies but without a start date start when their latest dependency ends (e.g. after:api, 3d)
+func resolveStart(projects []Project, i int, resolved []bool, dateFormat string) (time.Time, bool) {
+	expr := projects[i].StartExpression
+
+	if expr == "" && len(projects[i].DependsOn) > 0 {
+		return findAnchor(projects, i, resolved, false)
+	}
+
+	if !isRelativeDate(expr) {
+		d, err := parseDateOrPeriod(expr, dateFormat)
+
+		return d.StartAt, err == nil
+	}
+
+	m := relativeDateRegexp.FindStringSubmatch(expr)
+
+	var days int
+	if m[2] != "" {
+		d, err := parseDays(m[4], m[5])
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		days = d
+		if m[3] == "-" {
+			days = -d
+		}
+	}
+
+	anchor, ok := findAnchor(projects, i, resolved, m[1] == "^")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return anchor.AddDate(0, 0, days), true
+}
+
+// findAnchor finds the date a relative start date of a project is calculated from
+// end dates are inclusive, so projects following others are anchored to the day after those end
+func findAnchor(projects []Project, i int, resolved []bool, fromParent bool) (time.Time, bool) {
+	isReady := func(j int) bool {
+		return j >= 0 && resolved[j] && projects[j].Dates != nil
+	}
+
+	if !fromParent && len(projects[i].DependsOn) > 0 {
+		var anchor time.Time
+
+		for _, ref := range projects[i].DependsOn {
+			j := findProject(projects, ref)
+			if !isReady(j) {
+				return time.Time{}, false
+			}
+
+			if projects[j].Dates.EndAt.After(anchor) {
+				anchor = projects[j].Dates.EndAt
+			}
+		}
+
+		return anchor.AddDate(0, 0, 1), true
+	}
+
+	parent, sibling := findParentAndSibling(projects, i)
+
+	if !fromParent && sibling >= 0 {
+		if !isReady(sibling) {
+			return time.Time{}, false
+		}
+
+		return projects[sibling].Dates.EndAt.AddDate(0, 0, 1), true
+	}
+
+	if !isReady(parent) {
+		return time.Time{}, false
+	}
+
+	return projects[parent].Dates.StartAt, true
+}
+
+// findParentAndSibling returns the index of the parent and the previous sibling of a project, -1 if not found
+func findParentAndSibling(projects []Project, i int) (int, int) {
+	sibling := -1
+	indentation := projects[i].Indentation
+
+	for j := i - 1; j >= 0; j-- {
+		if projects[j].Indentation > indentation {
+			continue
+		}
+
+		if projects[j].Indentation == indentation {
+			if sibling < 0 {
+				sibling = j
+			}
+
+			continue
+		}
+
+		return j, sibling
+	}
+
+	return -1, sibling
+}
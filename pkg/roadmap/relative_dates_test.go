@@ -0,0 +1,158 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		part    string
+		want    int
+		wantErr bool
+	}{
+		{"days", "10d", 10, false},
+		{"weeks", "3w", 21, false},
+		{"missing unit", "10", 0, true},
+		{"unknown unit", "10m", 0, true},
+		{"relative date", "+10d", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDuration(tt.part)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_isRelativeDate(t *testing.T) {
+	tests := []struct {
+		name string
+		part string
+		want bool
+	}{
+		{"empty", "", false},
+		{"parent start", "^", true},
+		{"after parent start", "^+1w", true},
+		{"before parent start", "^-3d", true},
+		{"after previous sibling", "+2w", true},
+		{"duration", "2w", false},
+		{"date", "2020-02-12", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isRelativeDate(tt.part))
+		})
+	}
+}
+
+func Test_resolveDateExpressions(t *testing.T) {
+	const dateFormat = "2006-01-02"
+
+	var (
+		d = func(day int) time.Time {
+			return time.Date(2020, 2, day, 0, 0, 0, 0, time.UTC)
+		}
+		dates = func(startDay, endDay int) *Dates {
+			return &Dates{StartAt: d(startDay), EndAt: d(endDay)}
+		}
+	)
+
+	tests := []struct {
+		name     string
+		projects []Project
+		want     []*Dates
+		want1    []int
+	}{
+		{
+			"no expressions",
+			[]Project{
+				{Title: "foo", Dates: dates(1, 5)},
+				{Title: "bar"},
+			},
+			[]*Dates{dates(1, 5), nil},
+			nil,
+		},
+		{
+			"duration",
+			[]Project{
+				{Title: "foo", StartExpression: "2020-02-01", EndExpression: "1w"},
+			},
+			[]*Dates{dates(1, 7)},
+			nil,
+		},
+		{
+			"inclusive end dates",
+			[]Project{
+				{Title: "foo", StartExpression: "2020-02-01", EndExpression: "10d"},
+				{Title: "bar", StartExpression: "+0d", EndExpression: "1d"},
+				{Title: "baz", EndExpression: "2d", DependsOn: []string{"bar"}},
+			},
+			[]*Dates{dates(1, 10), dates(11, 11), dates(12, 13)},
+			nil,
+		},
+		{
+			"relative to parent and previous sibling",
+			[]Project{
+				{Title: "foo", Dates: dates(3, 20)},
+				{Title: "bar", Indentation: 1, StartExpression: "^+1d", EndExpression: "2d"},
+				{Title: "baz", Indentation: 2, StartExpression: "^", EndExpression: "1d"},
+				{Title: "quix", Indentation: 1, StartExpression: "+1w", EndExpression: "2020-02-20"},
+				{Title: "quux", Indentation: 1, StartExpression: "-1d", EndExpression: "3d"},
+			},
+			[]*Dates{dates(3, 20), dates(4, 5), dates(4, 4), dates(13, 20), dates(20, 22)},
+			nil,
+		},
+		{
+			"relative to dependencies defined later",
+			[]Project{
+				{Title: "foo", StartExpression: "+2d", EndExpression: "1d", DependsOn: []string{"bar", "baz"}},
+				{Title: "bar", StartExpression: "2020-02-01", EndExpression: "3d"},
+				{Title: "baz", Dates: dates(1, 2)},
+			},
+			[]*Dates{dates(6, 6), dates(1, 3), dates(1, 2)},
+			nil,
+		},
+		{
+			"duration only after dependencies",
+			[]Project{
+				{Title: "foo", Dates: dates(1, 10)},
+				{Title: "bar", StartExpression: "2020-02-01", EndExpression: "1w"},
+				{Title: "baz", EndExpression: "3d", DependsOn: []string{"foo", "bar"}},
+			},
+			[]*Dates{dates(1, 10), dates(1, 7), dates(11, 13)},
+			nil,
+		},
+		{
+			"unresolvable",
+			[]Project{
+				{Title: "foo", StartExpression: "^", EndExpression: "1d"},
+				{Title: "bar", StartExpression: "2020-02-01"},
+				{Title: "baz", StartExpression: "+1d", EndExpression: "1d", DependsOn: []string{"foo"}},
+			},
+			[]*Dates{nil, nil, nil},
+			[]int{0, 1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got1 := resolveDateExpressions(tt.projects, dateFormat)
+
+			var got []*Dates
+			for _, p := range tt.projects {
+				got = append(got, p.Dates)
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+		})
+	}
+}
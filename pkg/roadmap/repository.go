@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-pg/pg"
 	_ "github.com/lib/pq"
 
 	"github.com/peteraba/roadmapper/pkg/code"
@@ -16,6 +17,7 @@ import (
 
 type DbReadWriter interface {
 	Get(c code.Code) (*Roadmap, error)
+	GetLatest(c code.Code) (*Roadmap, error)
 	Create(roadmap Roadmap) error
 }
 
@@ -40,6 +42,9 @@ func (drw Repository) Get(code code.Code) (*Roadmap, error) {
 		return nil, herr.NewFromError(err, http.StatusInternalServerError)
 	}
 
+	// roadmaps stored before migration 06 might still use legacy date formats
+	roadmap.DateFormat = normalizeDateFormat(roadmap.DateFormat)
+
 	roadmap.UpdatedAt = time.Now()
 	_, err = db.Exec("UPDATE roadmaps SET accessed_at = NOW() WHERE id = ?", roadmap.ID)
 	if err != nil {
@@ -49,6 +54,31 @@ func (drw Repository) Get(code code.Code) (*Roadmap, error) {
 	return roadmap, nil
 }
 
+// GetLatest retrieves the latest version of a Roadmap from the database, which is the roadmap created last among the
+// roadmap and all the roadmaps created by editing it or any of its later versions
+func (drw Repository) GetLatest(c code.Code) (*Roadmap, error) {
+	db := drw.Connect()
+	defer db.Close()
+
+	var id uint64
+
+	_, err := db.QueryOne(pg.Scan(&id), `WITH RECURSIVE versions AS (
+			SELECT id, created_at FROM roadmaps WHERE id = ?
+			UNION ALL
+			SELECT r.id, r.created_at FROM roadmaps r INNER JOIN versions v ON r.prev_id = v.id
+		)
+		SELECT id FROM versions ORDER BY created_at DESC, id DESC LIMIT 1`, c.ID())
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return nil, herr.NewFromError(err, http.StatusNotFound)
+		}
+
+		return nil, herr.NewFromError(err, http.StatusInternalServerError)
+	}
+
+	return drw.Get(code.Code64(id))
+}
+
 // Create writes a roadmap to the database
 func (drw Repository) Create(roadmap Roadmap) error {
 	db := drw.Connect()
@@ -163,6 +163,73 @@ func TestIntegration_Repository(t *testing.T) {
 		}
 	})
 
+	t.Run("GetLatest", func(t *testing.T) {
+		var prevID uint64 = 123
+
+		r0 := Roadmap{ID: 123, Title: "Foo", DateFormat: "2006-01-02"}
+		r1 := Roadmap{ID: 124, PrevID: &prevID, Title: "Bar", DateFormat: "2006-01-02"}
+
+		type args struct {
+			code code.Code
+		}
+		tests := []struct {
+			name    string
+			fixture []interface{}
+			args    args
+			want    *Roadmap
+			wantErr bool
+		}{
+			{
+				"not found",
+				[]interface{}{},
+				args{code.Code64(123)},
+				nil,
+				true,
+			},
+			{
+				"no later version",
+				[]interface{}{
+					&r0,
+				},
+				args{code.Code64(123)},
+				&r0,
+				false,
+			},
+			{
+				"later version",
+				[]interface{}{
+					&r0,
+					&r1,
+				},
+				args{code.Code64(123)},
+				&r1,
+				false,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				defer reset()
+				reset(tt.fixture...)
+
+				repo := Repository{baseRepo}
+
+				got, err := repo.GetLatest(tt.args.code)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("GetLatest() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if tt.want != nil && got != nil {
+					got.AccessedAt = tt.want.AccessedAt
+					got.CreatedAt = tt.want.CreatedAt
+					got.UpdatedAt = tt.want.UpdatedAt
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("GetLatest() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
 	t.Run("Create", func(t *testing.T) {
 		r0 := Roadmap{ID: 123, Title: "Foo", DateFormat: "2006-01-02"}
 		r1 := Roadmap{ID: 123, Title: "Foo", DateFormat: "2006-01-02"}
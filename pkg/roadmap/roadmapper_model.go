@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"math"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,13 +23,19 @@ type Dates struct {
 
 // Roadmap represents a roadmap, the main entity of Roadmapper
 type RoadmapExchange struct {
-	ID         string      `json:"id,omitempty"`
-	PrevID     *string     `json:"prev_id,omitempty"`
-	Title      string      `json:"title"`
-	DateFormat string      `json:"date_format"`
-	BaseURL    string      `json:"base_url,omitempty"`
-	Projects   []Project   `json:"projects,omitempty"`
-	Milestones []Milestone `json:"milestones,omitempty"`
+	ID             string           `json:"id,omitempty"`
+	PrevID         *string          `json:"prev_id,omitempty"`
+	Title          string           `json:"title"`
+	DateFormat     string           `json:"date_format"`
+	BaseURL        string           `json:"base_url,omitempty"`
+	Theme          string           `json:"theme,omitempty"`
+	Progress       ProgressStrategy `json:"progress,omitempty"`
+	Timezone       string           `json:"timezone,omitempty"`
+	Projects       []Project        `json:"projects,omitempty"`
+	Milestones     []Milestone      `json:"milestones,omitempty"`
+	Comments       []string         `json:"comments,omitempty"`
+	HeaderComments []string         `json:"header_comments,omitempty"`
+	Diagnostics    Diagnostics      `json:"diagnostics,omitempty"`
 }
 
 func (re RoadmapExchange) ToRoadmap() Roadmap {
@@ -44,17 +52,30 @@ func (re RoadmapExchange) ToRoadmap() Roadmap {
 		}
 	}
 
-	return Roadmap{
-		PrevID:     prevID,
-		Title:      re.Title,
-		DateFormat: re.DateFormat,
-		BaseURL:    re.BaseURL,
-		Projects:   re.Projects,
-		Milestones: re.Milestones,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-		AccessedAt: now,
+	// calculated estimates are only part of the exchange format, values received are dropped, see ToExchange
+	var projects []Project
+	for _, p := range re.Projects {
+		p.EstimateTotal, p.EstimateRemaining = 0, 0
+		projects = append(projects, p)
 	}
+
+	// included projects and milestones are dropped, as includes are resolved each time a roadmap is loaded
+	return Roadmap{
+		PrevID:         prevID,
+		Title:          re.Title,
+		DateFormat:     normalizeDateFormat(re.DateFormat),
+		BaseURL:        re.BaseURL,
+		Theme:          re.Theme,
+		Progress:       re.Progress,
+		Timezone:       re.Timezone,
+		Projects:       projects,
+		Milestones:     re.Milestones,
+		Comments:       re.Comments,
+		HeaderComments: re.HeaderComments,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		AccessedAt:     now,
+	}.withoutIncluded()
 }
 
 // Roadmap represents a roadmap, the main entity of Roadmapper
@@ -64,11 +85,17 @@ type Roadmap struct {
 	Title      string
 	DateFormat string
 	BaseURL    string
+	Theme      string
+	Progress   ProgressStrategy
+	Timezone   string
 	Projects   []Project
 	Milestones []Milestone
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	AccessedAt time.Time
+	Comments   []string
+	// HeaderComments are the comments found in the header of a Content, they are written back to the header
+	HeaderComments []string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	AccessedAt     time.Time
 }
 
 func (r Roadmap) ToExchange() RoadmapExchange {
@@ -84,50 +111,104 @@ func (r Roadmap) ToExchange() RoadmapExchange {
 		prevID = &cstr
 	}
 
+	// projects are copied so that calculated estimates are only part of the exchange format
+	projects := append([]Project(nil), r.Projects...)
+	rollUpEstimates(projects)
+
 	return RoadmapExchange{
-		ID:         id,
-		PrevID:     prevID,
-		Title:      r.Title,
-		DateFormat: r.DateFormat,
-		BaseURL:    r.BaseURL,
-		Projects:   r.Projects,
-		Milestones: r.Milestones,
+		ID:             id,
+		PrevID:         prevID,
+		Title:          r.Title,
+		DateFormat:     r.DateFormat,
+		BaseURL:        r.BaseURL,
+		Theme:          r.Theme,
+		Progress:       r.Progress,
+		Timezone:       r.Timezone,
+		Projects:       projects,
+		Milestones:     r.Milestones,
+		Comments:       r.Comments,
+		HeaderComments: r.HeaderComments,
 	}
 }
 
 // Project represents a project that belongs to a Roadmap
 type Project struct {
-	Indentation uint8       `json:"indentation"`
-	Title       string      `json:"title"`
-	Dates       *Dates      `json:"dates,omitempty"`
-	Color       *color.RGBA `json:"color,omitempty"`
-	Percentage  uint8       `json:"percentage"`
-	URLs        []string    `json:"urls,omitempty"` // nolint
-	Milestone   uint8       `json:"milestone,omitempty"`
+	Indentation     uint8       `json:"indentation"`
+	Title           string      `json:"title"`
+	Dates           *Dates      `json:"dates,omitempty"`
+	Phases          []Phase     `json:"phases,omitempty"`
+	StartExpression string      `json:"start_expression,omitempty"`
+	EndExpression   string      `json:"end_expression,omitempty"`
+	Color           *color.RGBA `json:"color,omitempty"`
+	Percentage      uint8       `json:"percentage"`
+	Weight          float64     `json:"weight,omitempty"`
+	Estimate        float64     `json:"estimate,omitempty"`
+	Status          Status      `json:"status,omitempty"`
+	Owners          []string    `json:"owners,omitempty"`
+	Labels          []string    `json:"labels,omitempty"`
+	Fields          Fields      `json:"fields,omitempty"`
+	URLs            []string    `json:"urls,omitempty"` // nolint
+	Milestone       uint8       `json:"milestone,omitempty"`
+	MilestoneRef    string      `json:"milestone_ref,omitempty"`
+	ID              string      `json:"id,omitempty"`
+	DependsOn       []string    `json:"depends_on,omitempty"`
+	Include         string      `json:"include,omitempty"`
+	Comments        []string    `json:"comments,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	// IncludedFrom is the code of the included roadmap the project comes from, see resolveIncludes
+	IncludedFrom string `json:"included_from,omitempty"`
+
+	// EstimateTotal and EstimateRemaining are calculated from the estimates of sub-projects, see rollUpEstimates
+	EstimateTotal     float64 `json:"estimate_total,omitempty"`
+	EstimateRemaining float64 `json:"estimate_remaining,omitempty"`
 }
 
 // Milestone represents a milestone set for the roadmap
 type Milestone struct {
-	Title      string      `json:"title"`
-	DeadlineAt *time.Time  `json:"deadline_at,omitempty"`
-	Color      *color.RGBA `json:"color,omitempty"`
-	URLs       []string    `json:"urls,omitempty"` // nolint
+	Key         string      `json:"key,omitempty"`
+	Title       string      `json:"title"`
+	DeadlineAt  *time.Time  `json:"deadline_at,omitempty"`
+	Color       *color.RGBA `json:"color,omitempty"`
+	URLs        []string    `json:"urls,omitempty"` // nolint
+	Fields      Fields      `json:"fields,omitempty"`
+	Comments    []string    `json:"comments,omitempty"`
+	Comment     string      `json:"comment,omitempty"`
+	Description string      `json:"description,omitempty"`
+	// IncludedFrom is the code of the included roadmap the milestone comes from, see resolveIncludes
+	IncludedFrom string `json:"included_from,omitempty"`
 }
 
 // Content represents a raw string version of a roadmap
 type Content string
 
 // ToLines splits a Content into queries, a slice of strings
+// CRLF line endings (e.g. sent by browsers submitting a textarea) are treated the same way as LF line endings
 func (c Content) ToLines() []string {
 	if len(c) == 0 {
 		return nil
 	}
 
-	return strings.Split(string(c), "\n")
+	return strings.Split(strings.ReplaceAll(string(c), "\r\n", "\n"), "\n")
 }
 
 // ToRoadmap converts a Content to a Roadmap ready to be persisted or to be turned into a VisualRoadmap which can then be rendered
+// parts of the content which can not be parsed are ignored, use Parse to find out about them
 func (c Content) ToRoadmap(id uint64, prevID *uint64, title, dateFormat, baseUrl string, now time.Time) Roadmap {
+	r, _ := c.Parse(id, prevID, title, dateFormat, baseUrl, now)
+
+	return r
+}
+
+// Parse converts a Content to a Roadmap and also returns diagnostics about the parts of the content which could not
+// be parsed or which are inconsistent
+// settings found in the header of the content are only used if no title, date format or base url is provided
+// if no date format is provided, the one matching the most dates of the content is used, see DetectDateFormat
+func (c Content) Parse(id uint64, prevID *uint64, title, dateFormat, baseUrl string, now time.Time) (Roadmap, Diagnostics) {
+	settings, c, ds := c.splitHeader()
+	c, descriptions := c.extractDescriptions()
+	c, comments := c.extractComments()
+
 	r := Roadmap{
 		ID:         id,
 		PrevID:     prevID,
@@ -139,13 +220,35 @@ func (c Content) ToRoadmap(id uint64, prevID *uint64, title, dateFormat, baseUrl
 		AccessedAt: now,
 	}
 
+	settings.apply(&r)
+	r.DateFormat = normalizeDateFormat(r.DateFormat)
+
+	detection, detected := c.DetectDateFormat(), r.DateFormat == ""
+	if detected {
+		r.DateFormat = detection.formatOrDefault()
+	}
+	ds = append(ds, detection.diagnostics(r.DateFormat, detected)...)
+
 	indentation := c.findIndentation()
 
-	r.Projects = c.toProjects(indentation, r.DateFormat, r.BaseURL)
+	projects, ds2 := c.toProjects(indentation, r.DateFormat, r.BaseURL)
+	r.Projects = projects
+	ds = append(ds, ds2...)
 
-	r.Milestones = c.toMilestones(r.DateFormat, r.BaseURL)
+	milestones, ds3 := c.toMilestones(r.DateFormat, r.BaseURL)
+	r.Milestones = milestones
+	ds = append(ds, ds3...)
 
-	return r
+	r.attachComments(c, comments)
+	ds = append(ds, r.attachDescriptions(c, descriptions)...)
+
+	projectLines := c.lineNumbers(isLineProject)
+	loc := locator{projectLines: projectLines, milestoneLines: c.lineNumbers(isLineMilestone)}
+	ds = append(ds, r.validateMilestoneReferences(loc)...)
+	ds = append(ds, r.validateDependencies(loc)...)
+	ds = append(ds, r.resolveRelativeDates(loc)...)
+
+	return r, ds.sorted()
 }
 
 // findIndentation looks at the beginning of the queries of Content and return the first string of spaces and tabs found
@@ -169,40 +272,237 @@ func (c Content) findIndentation() string {
 	return "\t"
 }
 
-// toProjects converts Content to a slice of projects
-func (c Content) toProjects(indentation, dateFormat, baseUrl string) []Project {
-	var projects []Project
+var errFirstProjectIndented = errors.New("the first project can not be indented, as it has no parent")
 
-	for _, line := range c.ToLines() {
-		var dates *Dates
+// toProjects converts Content to a slice of projects
+func (c Content) toProjects(indentation, dateFormat, baseUrl string) ([]Project, Diagnostics) {
+	var (
+		projects        []Project
+		ds              Diagnostics
+		prevIndentation uint8
+	)
 
+	for i, line := range c.ToLines() {
 		if !isLineProject(line) {
 			continue
 		}
 
-		indentation, title, extra := splitLine(line, indentation)
+		ind, title, extra := splitLine(line, indentation)
+
+		switch {
+		case len(projects) == 0 && ind > 0:
+			ds = append(ds, Diagnostic{Line: i + 1, Column: 1, Severity: SeverityError, Message: errFirstProjectIndented.Error()})
+		case len(projects) > 0 && ind > prevIndentation+1:
+			ds = append(ds, Diagnostic{Line: i + 1, Column: 1, Severity: SeverityWarning, Message: "project is indented more than one level deeper than the previous one"})
+		}
+		prevIndentation = ind
+
+		e, ds2 := parseExtra(extra, i+1, extraColumn(line, extra), dateFormat, baseUrl)
+		ds = append(ds, ds2...)
+
+		p := e.toProject(ind, title, dateFormat)
+		if e.startAt != nil && e.endAt != nil && p.Dates != nil && p.Dates.EndAt.Before(p.Dates.StartAt) {
+			ds = append(ds, Diagnostic{Line: i + 1, Column: extraColumn(line, extra), Severity: SeverityError, Message: "end date is before start date", Token: extra})
+		}
+
+		projects = append(projects, p)
+	}
+
+	return projects, ds
+}
+
+// lineNumbers returns the line numbers (starting with 1) of the lines matched by the given function
+func (c Content) lineNumbers(match func(string) bool) []int {
+	var numbers []int
+
+	for i, line := range c.ToLines() {
+		if match(line) {
+			numbers = append(numbers, i+1)
+		}
+	}
+
+	return numbers
+}
+
+// validateMilestoneReferences checks if milestone keys are unique and all milestones referenced by projects exist
+func (r Roadmap) validateMilestoneReferences(loc locator) Diagnostics {
+	var (
+		ds   Diagnostics
+		keys = map[string]int{}
+	)
+
+	for i, m := range r.Milestones {
+		if m.Key == "" {
+			continue
+		}
+
+		if j, ok := keys[m.Key]; ok {
+			ds = append(ds, loc.milestone(i, SeverityError, "id is already used on "+loc.describeMilestone(j), idPrefix+m.Key))
+			continue
+		}
+
+		keys[m.Key] = i
+	}
+
+	for i, p := range r.Projects {
+		if p.MilestoneRef == "" && p.Milestone == 0 {
+			continue
+		}
+
+		if findMilestone(r.Milestones, p) >= 0 {
+			continue
+		}
+
+		message, token := fmt.Sprintf("milestone %d does not exist", p.Milestone), fmt.Sprintf("|%d", p.Milestone)
+		if p.MilestoneRef != "" {
+			message, token = fmt.Sprintf("milestone %s does not exist", p.MilestoneRef), "|"+p.MilestoneRef
+		}
+
+		ds = append(ds, loc.project(i, SeverityError, message, token))
+	}
+
+	return ds
+}
+
+// findMilestone returns the index of the milestone referenced by a project, -1 if the project does not reference an
+// existing milestone
+// milestones can be referenced by their key or title (e.g. |beta, |Beta release), keys take precedence over titles,
+// numeric references (e.g. |2) are kept for backwards compatibility and refer to the position of the milestone
+func findMilestone(milestones []Milestone, p Project) int {
+	if p.MilestoneRef == "" {
+		if p.Milestone == 0 || int(p.Milestone) > len(milestones) {
+			return -1
+		}
+
+		return int(p.Milestone) - 1
+	}
+
+	for i, m := range milestones {
+		if m.Key == p.MilestoneRef {
+			return i
+		}
+	}
+
+	for i, m := range milestones {
+		if m.Title == p.MilestoneRef {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// withPositionalMilestone replaces numeric milestone references of a project (e.g. a reference to a milestone titled
+// "2") with the position of the milestone referenced, as numeric references are parsed as positions (e.g. |2)
+func (p Project) withPositionalMilestone(milestones []Milestone) Project {
+	if _, err := parseMilestone("|" + p.MilestoneRef); err != nil {
+		return p
+	}
+
+	if i := findMilestone(milestones, p); i >= 0 && i < math.MaxUint8 {
+		p.Milestone, p.MilestoneRef = uint8(i+1), ""
+	}
+
+	return p
+}
+
+// findProject returns the index of the project referenced either by its ID or by its title
+// IDs take precedence over titles, -1 is returned if no project is found
+func findProject(projects []Project, ref string) int {
+	for i, p := range projects {
+		if p.ID == ref {
+			return i
+		}
+	}
+
+	for i, p := range projects {
+		if p.Title == ref {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// validateDependencies checks if all dependencies of projects exist and that projects don't depend on themselves
+// either directly or indirectly
+func (r Roadmap) validateDependencies(loc locator) Diagnostics {
+	var (
+		ds   Diagnostics
+		deps = make([][]int, len(r.Projects))
+		ids  = map[string]int{}
+	)
+
+	for i, p := range r.Projects {
+		if p.ID == "" {
+			continue
+		}
+
+		if j, ok := ids[p.ID]; ok {
+			ds = append(ds, loc.project(i, SeverityError, "id is already used on "+loc.describeProject(j), idPrefix+p.ID))
+			continue
+		}
 
-		startAt, endAt, c, urls, percent, milestone := parseExtra(extra, dateFormat, baseUrl)
+		ids[p.ID] = i
+	}
 
-		if startAt != nil && endAt != nil {
-			dates = &Dates{StartAt: *startAt, EndAt: *endAt}
+	for i, p := range r.Projects {
+		for _, d := range p.DependsOn {
+			j := findProject(r.Projects, d)
+			if j < 0 {
+				ds = append(ds, loc.project(i, SeverityError, "dependency not found", afterPrefix+d))
+				continue
+			}
+
+			deps[i] = append(deps[i], j)
 		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(r.Projects))
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = visiting
 
-		projects = append(
-			projects,
-			Project{
-				Indentation: indentation,
-				Title:       title,
-				Dates:       dates,
-				Color:       c,
-				Percentage:  percent,
-				URLs:        urls,
-				Milestone:   milestone,
-			},
-		)
+		for _, j := range deps[i] {
+			if state[j] == visiting {
+				return true
+			}
+
+			if state[j] == unvisited && visit(j) {
+				return true
+			}
+		}
+
+		state[i] = visited
+
+		return false
 	}
 
-	return projects
+	for i := range r.Projects {
+		if state[i] != unvisited {
+			continue
+		}
+
+		if visit(i) {
+			ds = append(ds, loc.project(i, SeverityError, "circular dependency found", r.Projects[i].Title))
+
+			// projects in the cycle are marked as visited to avoid reporting the same cycle multiple times
+			for j := range state {
+				if state[j] == visiting {
+					state[j] = visited
+				}
+			}
+		}
+	}
+
+	return ds
 }
 
 // ToContent converts a Roadmap into a Content
@@ -210,12 +510,17 @@ func (r Roadmap) ToContent() Content {
 	return Content(r.String())
 }
 
-// String converts a Roadmap into a string
+// String converts a Roadmap into a string, roadmap-level settings are written into a header
 func (r Roadmap) String() string {
-	var lines []string
+	return r.toString()
+}
+
+// toString converts a Roadmap into a string, roadmap-level settings listed in exclude are left out of the header
+func (r Roadmap) toString(exclude ...string) string {
+	lines := r.headerLines(exclude...)
 
 	for _, p := range r.Projects {
-		lines = append(lines, p.String(r.DateFormat))
+		lines = append(lines, p.withPositionalMilestone(r.Milestones).String(r.DateFormat))
 	}
 
 	if len(r.Projects) > 0 && len(r.Milestones) > 0 {
@@ -226,6 +531,10 @@ func (r Roadmap) String() string {
 		lines = append(lines, m.String(r.DateFormat))
 	}
 
+	for _, c := range r.Comments {
+		lines = append(lines, commentLine(c))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -235,30 +544,82 @@ func (p Project) String(dateFormat string) string {
 
 	var extra []string
 
-	if p.Dates != nil {
-		extra = append(extra, p.Dates.StartAt.Format(dateFormat))
-		extra = append(extra, p.Dates.EndAt.Format(dateFormat))
+	if p.hasDateExpressions() {
+		if p.StartExpression != "" {
+			extra = append(extra, p.StartExpression)
+		}
+		if p.EndExpression != "" {
+			extra = append(extra, p.EndExpression)
+		}
+	} else if p.Dates != nil && !p.hasDerivedDates() {
+		extra = append(extra, formatDate(p.Dates.StartAt, dateFormat))
+		extra = append(extra, formatDate(p.Dates.EndAt, dateFormat))
+	}
+
+	for _, ph := range p.Phases {
+		extra = append(extra, escapeExtra(ph.String(dateFormat)))
 	}
 
 	if p.Percentage > 0 {
 		extra = append(extra, fmt.Sprintf("%d%%", p.Percentage))
 	}
 
+	if p.Weight > 0 {
+		extra = append(extra, formatWeight(p.Weight))
+	}
+
+	if p.Estimate > 0 {
+		extra = append(extra, formatEstimate(p.Estimate))
+	}
+
+	if p.Status != "" {
+		extra = append(extra, string(p.Status))
+	}
+
+	for _, o := range p.Owners {
+		extra = append(extra, ownerPrefix+escapeExtra(o))
+	}
+
+	for _, l := range p.Labels {
+		extra = append(extra, labelPrefix+escapeExtra(l))
+	}
+
+	for _, f := range p.Fields {
+		extra = append(extra, escapeExtra(f.String()))
+	}
+
 	if p.Color != nil {
 		extra = append(extra, colors.ToHexa(p.Color))
 	}
 
-	extra = append(extra, p.URLs...)
+	for _, u := range p.URLs {
+		extra = append(extra, escapeExtra(u))
+	}
 
-	if p.Milestone > 0 {
+	if p.MilestoneRef != "" {
+		extra = append(extra, "|"+escapeExtra(p.MilestoneRef))
+	} else if p.Milestone > 0 {
 		extra = append(extra, fmt.Sprintf("|%d", p.Milestone))
 	}
 
-	if len(extra) == 0 {
-		return fmt.Sprintf("%s%s", indentation, p.Title)
+	if p.ID != "" {
+		extra = append(extra, idPrefix+escapeExtra(p.ID))
+	}
+
+	for _, d := range p.DependsOn {
+		extra = append(extra, afterPrefix+escapeExtra(d))
+	}
+
+	if p.Include != "" {
+		extra = append(extra, includePrefix+p.Include)
 	}
 
-	return fmt.Sprintf("%s%s [%s]", indentation, p.Title, strings.Join(extra, ", "))
+	line := fmt.Sprintf("%s%s", indentation, escapeTitle(p.Title))
+	if len(extra) > 0 {
+		line = fmt.Sprintf("%s%s [%s]", indentation, escapeTitle(p.Title), strings.Join(extra, ", "))
+	}
+
+	return withDescription(withComments(line, indentation, p.Comments, p.Comment), indentation, p.Description)
 }
 
 // String converts a Milestone into a string
@@ -266,20 +627,31 @@ func (m Milestone) String(dateFormat string) string {
 	var extra []string
 
 	if m.DeadlineAt != nil {
-		extra = append(extra, m.DeadlineAt.Format(dateFormat))
+		extra = append(extra, formatDate(*m.DeadlineAt, dateFormat))
 	}
 
 	if m.Color != nil {
 		extra = append(extra, colors.ToHexa(m.Color))
 	}
 
-	extra = append(extra, m.URLs...)
+	for _, u := range m.URLs {
+		extra = append(extra, escapeExtra(u))
+	}
+
+	for _, f := range m.Fields {
+		extra = append(extra, escapeExtra(f.String()))
+	}
+
+	if m.Key != "" {
+		extra = append(extra, idPrefix+escapeExtra(m.Key))
+	}
 
-	if len(extra) == 0 {
-		return fmt.Sprintf("|%s", m.Title)
+	line := fmt.Sprintf("|%s", escapeTitle(m.Title))
+	if len(extra) > 0 {
+		line = fmt.Sprintf("|%s [%s]", escapeTitle(m.Title), strings.Join(extra, ", "))
 	}
 
-	return fmt.Sprintf("|%s [%s]", m.Title, strings.Join(extra, ", "))
+	return withDescription(withComments(line, "", m.Comments, m.Comment), "", m.Description)
 }
 
 // ToDates converts a Roadmap into a Dates pointer
@@ -287,21 +659,12 @@ func (r Roadmap) ToDates() *Dates {
 	var d *Dates
 
 	for _, p := range r.Projects {
-		if p.Dates == nil {
+		span := p.span()
+		if span == nil {
 			continue
 		}
 
-		if d == nil {
-			d = &Dates{StartAt: p.Dates.StartAt, EndAt: p.Dates.EndAt}
-		}
-
-		if p.Dates.StartAt.Before(d.StartAt) {
-			d.StartAt = p.Dates.StartAt
-		}
-
-		if p.Dates.EndAt.After(d.EndAt) {
-			d.EndAt = p.Dates.EndAt
-		}
+		d = extendDates(d, *span)
 	}
 
 	for _, m := range r.Milestones {
@@ -326,36 +689,39 @@ func (r Roadmap) ToDates() *Dates {
 }
 
 // toMilestones converts Content into a slice of Milestones
-func (c Content) toMilestones(dateFormat, baseUrl string) []Milestone {
-	var milestones []Milestone
+func (c Content) toMilestones(dateFormat, baseUrl string) ([]Milestone, Diagnostics) {
+	var (
+		milestones []Milestone
+		ds         Diagnostics
+	)
 
-	for _, line := range c.ToLines() {
+	for i, line := range c.ToLines() {
 		if !isLineMilestone(line) {
 			continue
 		}
 
 		_, title, extra := splitLine(line, "")
 
-		deadlineAt, _, c, urls, _, _ := parseExtra(extra, dateFormat, baseUrl)
+		e, ds2 := parseExtra(extra, i+1, extraColumn(line, extra), dateFormat, baseUrl)
+		ds = append(ds, ds2...)
 
-		milestones = append(
-			milestones,
-			Milestone{
-				Title:      title,
-				DeadlineAt: deadlineAt,
-				Color:      c,
-				URLs:       urls,
-			},
-		)
+		if e.hasProjectData() {
+			ds = append(ds, Diagnostic{Line: i + 1, Column: extraColumn(line, extra), Severity: SeverityWarning, Message: errMilestoneProjectData.Error(), Token: extra})
+		}
+
+		milestones = append(milestones, e.toMilestone(title))
 	}
 
-	return milestones
+	return milestones, ds
 }
 
 // splitLine splits a Content line into a title and extra information, plus returns the indentation level found
+// the title returned is unescaped, the extra information is not, as escaped separators are needed to split it
 func splitLine(line, indentation string) (uint8, string, string) {
 	var n uint8
 
+	line = strings.TrimRight(line, "\r")
+
 	for indentation != "" {
 		if len(line) < len(indentation) {
 			break
@@ -373,14 +739,14 @@ func splitLine(line, indentation string) (uint8, string, string) {
 		line = line[1:]
 	}
 
-	lo := strings.LastIndex(line, "[")
-	lc := strings.LastIndex(line, "]")
+	lo := lastIndexUnescaped(line, '[')
+	lc := lastIndexUnescaped(line, ']')
 
 	if lc < 0 || lo < 0 || lc < lo {
-		return n, line, ""
+		return n, unescapeTitle(line), ""
 	}
 
-	return n, strings.Trim(line[:lo], "\t\r "), strings.Trim(line[lo+1:lc], "\t\r ")
+	return n, unescapeTitle(strings.Trim(line[:lo], "\t\r ")), strings.Trim(line[lo+1:lc], "\t\r ")
 }
 
 // isLineProject returns true if a given line appears to represent a project
@@ -417,72 +783,398 @@ func isLineMilestone(line string) bool {
 	return true
 }
 
-// parseExtra returns data found in extra parts of queries representing projects and milestones
-func parseExtra(extra, dateFormat, baseUrl string) (*time.Time, *time.Time, *color.RGBA, []string, uint8, uint8) {
-	parts := strings.Split(extra, ", ")
+// extraData represents the information found in the extra part of a line
+type extraData struct {
+	startAt, endAt     *time.Time
+	startExpr, endExpr string
+	phases             []Phase
+	color              *color.RGBA
+	urls               []string
+	percentage         uint8
+	weight             float64
+	estimate           float64
+	status             Status
+	owners             []string
+	labels             []string
+	fields             Fields
+	milestone          uint8
+	milestoneRef       string
+	id                 string
+	dependsOn          []string
+	include            string
+}
+
+var errMilestoneProjectData = errors.New("milestones only support a deadline, a color, urls, fields and an id")
+
+// toProject converts the extra information of a line into a project
+// dates are only set if they are given as dates or phases, relative dates and durations are kept as expressions
+func (e extraData) toProject(indentation uint8, title, dateFormat string) Project {
+	var dates *Dates
+
+	startExpression, endExpression := e.toExpressions(dateFormat)
+
+	if startExpression == "" && endExpression == "" && e.startAt != nil && e.endAt != nil {
+		dates = &Dates{StartAt: *e.startAt, EndAt: *e.endAt}
+	}
+
+	if startExpression == "" && endExpression == "" && e.startAt == nil && e.endAt == nil {
+		// projects with phases only span over all of their phases
+		dates = phasesSpan(e.phases)
+	}
+
+	return Project{
+		Indentation:     indentation,
+		Title:           title,
+		Dates:           dates,
+		Phases:          e.phases,
+		StartExpression: startExpression,
+		EndExpression:   endExpression,
+		Color:           e.color,
+		Percentage:      e.percentage,
+		Weight:          e.weight,
+		Estimate:        e.estimate,
+		Status:          e.status,
+		Owners:          e.owners,
+		Labels:          e.labels,
+		Fields:          e.fields,
+		URLs:            e.urls,
+		Milestone:       e.milestone,
+		MilestoneRef:    e.milestoneRef,
+		ID:              e.id,
+		DependsOn:       e.dependsOn,
+		Include:         e.include,
+	}
+}
 
+// toMilestone converts the extra information of a line into a milestone, the start date is used as deadline
+func (e extraData) toMilestone(title string) Milestone {
+	return Milestone{
+		Key:        e.id,
+		Title:      title,
+		DeadlineAt: e.startAt,
+		Color:      e.color,
+		URLs:       e.urls,
+		Fields:     e.fields,
+	}
+}
+
+// hasProjectData returns true if the extra information contains data only supported by projects
+func (e extraData) hasProjectData() bool {
+	return e.endAt != nil || len(e.phases) > 0 || e.startExpr != "" || e.endExpr != "" || e.percentage != 0 || e.weight != 0 || e.estimate != 0 || e.status != "" || len(e.owners) > 0 || len(e.labels) > 0 || e.milestone != 0 || e.milestoneRef != "" || len(e.dependsOn) > 0 || e.include != ""
+}
+
+// extraColumn returns the column (starting with 1) at which the extra part of a line starts
+func extraColumn(line, extra string) int {
+	if extra == "" {
+		return 0
+	}
+
+	return strings.LastIndex(line, extra) + 1
+}
+
+// parseExtra returns data found in extra parts of lines representing projects and milestones
+// problems found are returned as diagnostics, using the line and column given for the start of the extra part
+func parseExtra(extra string, line, column int, dateFormat, baseUrl string) (extraData, Diagnostics) {
 	var (
-		startAt, endAt *time.Time
-		urls           []string
-		c              *color.RGBA
-		percent        uint8
-		milestone      uint8
+		e  extraData
+		ds Diagnostics
 	)
 
-	for i := 0; i < len(parts); i++ {
-		if parts[i] == "" {
-			break
+	if extra == "" {
+		return e, nil
+	}
+
+	for _, part := range splitUnescaped(extra, ", ") {
+		var err error
+
+		e, err = parseExtraPart(unescape(part), e, dateFormat, baseUrl)
+		if err != nil {
+			ds = append(ds, newDiagnostic(line, column, err, unescape(part)))
 		}
 
-		startAt, endAt, urls, c, percent, milestone = parseExtraPart(parts[i], startAt, endAt, urls, c, percent, milestone, dateFormat, baseUrl)
+		column += len(part) + 2
 	}
 
-	return startAt, endAt, c, urls, percent, milestone
+	return e, ds
 }
 
+var (
+	errInvalidDate         = errors.New("invalid date")
+	errStartDateAlreadySet = errors.New("relative start date must come before other dates")
+)
+
 // parseExtraPart returns data found in one piece of extra information
-func parseExtraPart(part string, f, t *time.Time, u []string, c *color.RGBA, p, m uint8, dateFormat, baseUrl string) (*time.Time, *time.Time, []string, *color.RGBA, uint8, uint8) {
-	t2, err := time.Parse(dateFormat, part)
-	if err == nil {
-		if f == nil {
-			return &t2, t, u, c, p, m
+// the error returned describes why the piece could not be (fully) used, data might still be set even if an error is
+// returned
+func parseExtraPart(part string, e extraData, dateFormat, baseUrl string) (extraData, error) {
+	if part == "" {
+		return e, errEmptyExtra
+	}
+
+	if isPhase(part, dateFormat) {
+		ph, err := parsePhase(part, dateFormat)
+		if err != nil {
+			return e, err
 		}
 
-		return f, &t2, u, c, p, m
+		e.phases = append(e.phases, ph)
+
+		return e, nil
 	}
 
-	p2, err := parsePercentage(part)
+	t2, err := parseDate(dateFormat, part)
 	if err == nil {
-		return f, t, u, c, p2, m
+		if e.startAt == nil && e.startExpr == "" {
+			e.startAt = &t2
+
+			return e, nil
+		}
+
+		overwritten := e.endAt != nil || e.endExpr != ""
+		e.endAt = &t2
+		e.endExpr = ""
+
+		if overwritten {
+			return e, errEndDateOverwritten
+		}
+
+		return e, nil
 	}
 
-	m2, err := parseMilestone(part)
-	if err == nil {
-		return f, t, u, c, p, m2
+	if isDateLike(part) {
+		return e, fmt.Errorf("%w, expected format: %s", errInvalidDate, dateFormat)
 	}
 
-	c2, err := parseColor(part)
-	if err == nil {
-		return f, t, u, c2, p, m
+	if isPeriod(part) {
+		if _, err := parsePeriod(part); err != nil {
+			return e, err
+		}
+
+		if e.startAt == nil && e.startExpr == "" {
+			e.startExpr = part
+
+			return e, nil
+		}
+
+		overwritten := e.endAt != nil || e.endExpr != ""
+		e.endAt = nil
+		e.endExpr = part
+
+		if overwritten {
+			return e, errEndDateOverwritten
+		}
+
+		return e, nil
+	}
+
+	if isRelativeDate(part) {
+		if e.startAt != nil || e.startExpr != "" {
+			return e, errStartDateAlreadySet
+		}
+
+		e.startExpr = part
+
+		return e, nil
+	}
+
+	if isDuration(part) {
+		overwritten := e.endAt != nil || e.endExpr != ""
+		e.endAt = nil
+		e.endExpr = part
+
+		if overwritten {
+			return e, errEndDateOverwritten
+		}
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, idPrefix) {
+		id, err := parseReference(part, idPrefix)
+		if err != nil {
+			return e, err
+		}
+
+		e.id = id
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, afterPrefix) {
+		d, err := parseReference(part, afterPrefix)
+		if err != nil {
+			return e, err
+		}
+
+		e.dependsOn = append(e.dependsOn, d)
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, includePrefix) {
+		i, err := parseInclude(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.include = i
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, ownerPrefix) {
+		o, err := parseOwner(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.owners = append(e.owners, o)
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, labelPrefix) {
+		l, err := parseLabel(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.labels = append(e.labels, l)
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, weightPrefix) {
+		w, err := parseWeight(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.weight = w
+
+		return e, nil
+	}
+
+	if strings.HasPrefix(part, estimatePrefix) {
+		est, err := parseEstimate(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.estimate = est
+
+		return e, nil
+	}
+
+	if isField(part) {
+		f, err := parseField(part)
+		if err != nil {
+			return e, err
+		}
+
+		var overwritten bool
+		e.fields, overwritten = e.fields.set(f)
+
+		if overwritten {
+			return e, errFieldOverwritten
+		}
+
+		return e, nil
+	}
+
+	if part[len(part)-1] == '%' {
+		p2, err := parsePercentage(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.percentage = p2
+
+		return e, nil
+	}
+
+	if s, ok := parseStatus(part); ok {
+		e.status = s
+
+		return e, nil
+	}
+
+	if part[0] == '|' {
+		m2, err := parseMilestone(part)
+		if err == nil {
+			e.milestone = m2
+			e.milestoneRef = ""
+
+			return e, nil
+		}
+
+		ref := strings.Trim(part[1:], "\t ")
+		if ref == "" {
+			return e, err
+		}
+
+		e.milestone = 0
+		e.milestoneRef = ref
+
+		return e, nil
+	}
+
+	if part[0] == '#' {
+		c2, err := parseColor(part)
+		if err != nil {
+			return e, err
+		}
+
+		e.color = c2
+
+		return e, nil
 	}
 
 	parsedUrl, err := url.ParseRequestURI(part)
 	if err == nil && parsedUrl.Scheme != "" && parsedUrl.Host != "" {
-		return f, t, append(u, part), c, p, m
+		e.urls = append(e.urls, part)
+
+		return e, nil
 	}
 
 	if baseUrl != "" {
 		prefixedUrl := fmt.Sprintf("%s/%s", strings.TrimRight(baseUrl, "/"), strings.TrimLeft(part, "/"))
 		_, err = url.ParseRequestURI(prefixedUrl)
 		if err == nil {
-			return f, t, append(u, part), c, p, m
+			e.urls = append(e.urls, part)
+
+			return e, nil
 		}
 	}
 
-	return f, t, u, c, p, m
+	return e, errUnknownExtra
+}
+
+var dateLikeRegexp = regexp.MustCompile(`^\d{1,4}[-./]\d{1,2}[-./]\d{1,4}( \d{1,2}:\d{2})?$`)
+
+// isDateLike returns true if a string consists of three groups of digits separated by dots, dashes or slashes,
+// optionally followed by a time of day
+// such strings are expected to be dates, even if they can not be parsed using the date format of the roadmap
+func isDateLike(part string) bool {
+	return dateLikeRegexp.MatchString(part)
+}
+
+const (
+	idPrefix    = "id:"
+	afterPrefix = "after:"
+)
+
+// parseReference tries to parse a string as a prefixed reference (e.g. id:backend, after:Create prototype)
+func parseReference(part, prefix string) (string, error) {
+	ref := strings.Trim(part[len(prefix):], "\t ")
+	if ref == "" {
+		return "", fmt.Errorf("missing reference after %s", prefix)
+	}
+
+	return ref, nil
 }
 
-var errCannotParsePercentage = errors.New("can not parse string as percentage")
+var errCannotParsePercentage = errors.New("can not parse string as percentage (0-100%)")
 
 // parsePercentage tries to parse a string as a percentage between 0 and 100
 func parsePercentage(part string) (uint8, error) {
@@ -533,7 +1225,7 @@ func parseMilestone(part string) (uint8, error) {
 // parseColor tries to parse a string as a color in a hexadecimal representation (e.g #fa3, #ffaa33)
 func parseColor(part string) (*color.RGBA, error) {
 	if len(part) != 4 && len(part) != 7 {
-		return nil, errors.New("invalid hexa color length")
+		return nil, errors.New("invalid hexa color length, expected format: #rgb or #rrggbb")
 	}
 
 	if part[0] != '#' {
@@ -542,7 +1234,7 @@ func parseColor(part string) (*color.RGBA, error) {
 
 	s, err := colors.CharsToUint8(part[1:])
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse to uint8s: %w", err)
+		return nil, fmt.Errorf("invalid hexa color: %w", err)
 	}
 
 	return &color.RGBA{R: s[0], G: s[1], B: s[2], A: 255}, nil
@@ -1,6 +1,7 @@
 package roadmap
 
 import (
+	"errors"
 	"image/color"
 	"reflect"
 	"testing"
@@ -255,7 +256,7 @@ func TestContent_ToRoadmap(t *testing.T) {
 			"empty roadmap",
 			"",
 			args{},
-			Roadmap{},
+			Roadmap{DateFormat: "2006-01-02"},
 		},
 		{
 			"example",
@@ -321,6 +322,122 @@ Marketing
 	}
 }
 
+func TestContent_Parse(t *testing.T) {
+	now := time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		c     Content
+		want  []Project
+		want1 Diagnostics
+	}{
+		{
+			"empty roadmap",
+			"",
+			nil,
+			nil,
+		},
+		{
+			"diagnostics are sorted and have line numbers",
+			`Bring website online [2020-02-31]
+	Create server infrastructure [|2]
+			Select and purchase domain [2020-02-12, 2020-02-10]
+
+|Milestone 0.2 [2020-03-01, 50%]`,
+			[]Project{
+				{Title: "Bring website online"},
+				{Title: "Create server infrastructure", Indentation: 1, Milestone: 2},
+				{Title: "Select and purchase domain", Indentation: 3, Dates: &Dates{StartAt: time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC)}},
+			},
+			Diagnostics{
+				{Line: 1, Column: 23, Severity: SeverityError, Message: "invalid date, expected format: 2006-01-02", Token: "2020-02-31"},
+				{Line: 2, Column: 1, Severity: SeverityError, Message: "milestone 2 does not exist", Token: "|2"},
+				{Line: 3, Column: 1, Severity: SeverityWarning, Message: "project is indented more than one level deeper than the previous one"},
+				{Line: 3, Column: 32, Severity: SeverityError, Message: "end date is before start date", Token: "2020-02-12, 2020-02-10"},
+				{Line: 5, Column: 17, Severity: SeverityWarning, Message: "milestones only support a deadline, a color, urls, fields and an id", Token: "2020-03-01, 50%"},
+			},
+		},
+		{
+			"relative dates are resolved",
+			`Bring website online [2020-02-01, 6w]
+	Create server infrastructure [^, 10d]
+	Select and purchase domain [+2d, 1w]
+	Create website [+1w]`,
+			[]Project{
+				{Title: "Bring website online", StartExpression: "2020-02-01", EndExpression: "6w", Dates: &Dates{StartAt: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 3, 13, 0, 0, 0, 0, time.UTC)}},
+				{Title: "Create server infrastructure", Indentation: 1, StartExpression: "^", EndExpression: "10d", Dates: &Dates{StartAt: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC)}},
+				{Title: "Select and purchase domain", Indentation: 1, StartExpression: "+2d", EndExpression: "1w", Dates: &Dates{StartAt: time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 19, 0, 0, 0, 0, time.UTC)}},
+				{Title: "Create website", Indentation: 1, StartExpression: "+1w"},
+			},
+			Diagnostics{
+				{Line: 4, Column: 1, Severity: SeverityWarning, Message: "dates could not be calculated, a start and an end are needed and relative dates need a parent, a previous sibling or a dependency with dates", Token: "+1w"},
+			},
+		},
+		{
+			"milestones are referenced by key or title",
+			`Bring website online [|beta]
+Create server infrastructure [|Milestone 0.2]
+Select and purchase domain [|rc]
+
+|Milestone 0.1 [id:beta]
+|Milestone 0.2 [id:beta]`,
+			[]Project{
+				{Title: "Bring website online", MilestoneRef: "beta"},
+				{Title: "Create server infrastructure", MilestoneRef: "Milestone 0.2"},
+				{Title: "Select and purchase domain", MilestoneRef: "rc"},
+			},
+			Diagnostics{
+				{Line: 3, Column: 1, Severity: SeverityError, Message: "milestone rc does not exist", Token: "|rc"},
+				{Line: 6, Column: 1, Severity: SeverityError, Message: "id is already used on line 5", Token: "id:beta"},
+			},
+		},
+		{
+			"first project is indented",
+			`	Bring website online
+Create server infrastructure`,
+			[]Project{
+				{Title: "Bring website online", Indentation: 1},
+				{Title: "Create server infrastructure"},
+			},
+			Diagnostics{
+				{Line: 1, Column: 1, Severity: SeverityError, Message: errFirstProjectIndented.Error()},
+			},
+		},
+		{
+			"explicit settings take precedence over the header",
+			`---
+dateFormat: 02.01.2006
+---
+Bring website online [2020-02-01, 03.02.2020]`,
+			[]Project{
+				{Title: "Bring website online"},
+			},
+			Diagnostics{
+				{Line: 4, Column: 35, Severity: SeverityError, Message: "invalid date, expected format: 2006-01-02", Token: "03.02.2020"},
+			},
+		},
+		{
+			"periods are expanded",
+			`Bring website online [2021-Q3]
+	Create server infrastructure [2021-W27, 2021-08]
+	Select and purchase domain [2021-07-05, 2021-W28]`,
+			[]Project{
+				{Title: "Bring website online", StartExpression: "2021-Q3", Dates: &Dates{StartAt: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2021, 9, 30, 0, 0, 0, 0, time.UTC)}},
+				{Title: "Create server infrastructure", Indentation: 1, StartExpression: "2021-W27", EndExpression: "2021-08", Dates: &Dates{StartAt: time.Date(2021, 7, 5, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2021, 8, 31, 0, 0, 0, 0, time.UTC)}},
+				{Title: "Select and purchase domain", Indentation: 1, StartExpression: "2021-07-05", EndExpression: "2021-W28", Dates: &Dates{StartAt: time.Date(2021, 7, 5, 0, 0, 0, 0, time.UTC), EndAt: time.Date(2021, 7, 18, 0, 0, 0, 0, time.UTC)}},
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := tt.c.Parse(0, nil, "", "2006-01-02", "", now)
+			assert.Equal(t, tt.want, got.Projects)
+			assert.Equal(t, tt.want1, got1)
+		})
+	}
+}
+
 func TestContent_findIndentation(t *testing.T) {
 	tests := []struct {
 		name string
@@ -393,7 +510,7 @@ func TestContent_toProjects(t *testing.T) {
 			"Initial development",
 			args{"  ", "2006-01-02", "http://example.com/"},
 			[]Project{
-				{0, "Initial development", nil, nil, 0, nil, 0},
+				{Title: "Initial development"},
 			},
 		},
 		{
@@ -401,13 +518,13 @@ func TestContent_toProjects(t *testing.T) {
 			"Initial development [2020-02-12, 2020-02-20]",
 			args{"  ", "2006-01-02", "http://example.com/"},
 			[]Project{
-				{0, "Initial development", &Dates{startAt, endAt}, nil, 0, nil, 0},
+				{Title: "Initial development", Dates: &Dates{startAt, endAt}},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.c.toProjects(tt.args.indentation, tt.args.dateFormat, tt.args.baseUrl); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := tt.c.toProjects(tt.args.indentation, tt.args.dateFormat, tt.args.baseUrl); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("toProjects() = %v, want %v", got, tt.want)
 			}
 		})
@@ -513,6 +630,112 @@ func TestRoadmap_ToContent(t *testing.T) {
 			},
 			"Select and purchase domain [https://example.com/abc]",
 		},
+		{
+			"1 project with status",
+			fields{
+				Projects: []Project{
+					{
+						Title:      "Select and purchase domain",
+						Percentage: 20,
+						Status:     StatusAtRisk,
+					},
+				},
+			},
+			"Select and purchase domain [20%, at-risk]",
+		},
+		{
+			"1 project with weight",
+			fields{
+				Projects: []Project{
+					{
+						Title:      "Select and purchase domain",
+						Percentage: 20,
+						Weight:     2.5,
+					},
+				},
+			},
+			"Select and purchase domain [20%, weight=2.5]",
+		},
+		{
+			"1 project with owners",
+			fields{
+				Projects: []Project{
+					{
+						Title:  "Select and purchase domain",
+						Owners: []string{"peter", "anna"},
+					},
+				},
+			},
+			"Select and purchase domain [@peter, @anna]",
+		},
+		{
+			"1 project with labels",
+			fields{
+				Projects: []Project{
+					{
+						Title:  "Select and purchase domain",
+						Owners: []string{"peter"},
+						Labels: []string{"infra", "q3-commit"},
+					},
+				},
+			},
+			"Select and purchase domain [@peter, ~infra, ~q3-commit]",
+		},
+		{
+			"1 project with fields",
+			fields{
+				Projects: []Project{
+					{
+						Title:  "Select and purchase domain",
+						Labels: []string{"infra"},
+						Fields: Fields{{Key: "team", Value: "payments"}, {Key: "note", Value: "a, b"}},
+					},
+				},
+			},
+			"Select and purchase domain [~infra, team=payments, note=a\\, b]",
+		},
+		{
+			"1 project with id and dependencies",
+			fields{
+				Projects: []Project{
+					{
+						Title:     "Select and purchase domain",
+						ID:        "domain",
+						DependsOn: []string{"budget", "Find a name"},
+					},
+				},
+			},
+			"Select and purchase domain [id:domain, after:budget, after:Find a name]",
+		},
+		{
+			"1 project with relative dates",
+			fields{
+				DateFormat: "2006-01-02",
+				Projects: []Project{
+					{
+						Title:           "Select and purchase domain",
+						Dates:           &dates1,
+						StartExpression: "+2w",
+						EndExpression:   "8d",
+					},
+				},
+			},
+			"Select and purchase domain [+2w, 8d]",
+		},
+		{
+			"settings are written into a header",
+			fields{
+				DateFormat: "02.01.2006",
+				BaseURL:    "https://example.com/",
+				Projects: []Project{
+					{
+						Title: "Select and purchase domain",
+						Dates: &dates1,
+					},
+				},
+			},
+			"---\ndateFormat: 02.01.2006\nbaseUrl: https://example.com/\n---\nSelect and purchase domain [12.02.2020, 20.02.2020]",
+		},
 		{
 			"1 simple milestone",
 			fields{
@@ -768,13 +991,13 @@ func TestContent_toMilestones(t *testing.T) {
 			"|Initial milestone [2020-02-12]",
 			args{"2006-01-02", "http://example.com/"},
 			[]Milestone{
-				{"Initial milestone", &deadlineAt, nil, nil},
+				{Title: "Initial milestone", DeadlineAt: &deadlineAt},
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.c.toMilestones(tt.args.dateFormat, tt.args.baseUrl); !reflect.DeepEqual(got, tt.want) {
+			if got, _ := tt.c.toMilestones(tt.args.dateFormat, tt.args.baseUrl); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("toMilestones() = %v, want %v", got, tt.want)
 			}
 		})
@@ -943,12 +1166,7 @@ func Test_parseExtraPart(t *testing.T) {
 
 	type args struct {
 		part       string
-		f          *time.Time
-		t          *time.Time
-		u          []string
-		c          *color.RGBA
-		p          uint8
-		m          uint8
+		e          extraData
 		dateFormat string
 		baseUrl    string
 	}
@@ -961,164 +1179,305 @@ func Test_parseExtraPart(t *testing.T) {
 
 	nye, _ := time.Parse(dateFormat, nyeRaw)
 
-	_, _ = nye, now
-
 	tests := []struct {
-		name      string
-		args      args
-		startAt   *time.Time
-		endAt     *time.Time
-		urls      []string
-		percent   uint8
-		milestone uint8
-		wantColor *color.RGBA
+		name    string
+		args    args
+		want    extraData
+		wantErr error
 	}{
 		{
-			name:      "parse from",
-			args:      args{part: "2020-12-31", dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   &nye,
-			endAt:     nil,
-			urls:      nil,
-			percent:   0,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parse to",
-			args:      args{part: "2020-12-31", f: &nye, dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   &nye,
-			endAt:     &nye,
-			urls:      nil,
-			percent:   0,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parsing to overwrites existing to",
-			args:      args{part: "2020-12-31", f: &nye, t: &now, dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   &nye,
-			endAt:     &nye,
-			urls:      nil,
-			percent:   0,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parse url",
-			args:      args{part: url},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      []string{url},
-			percent:   0,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parsing url overwrites existing url",
-			args:      args{part: "asd", u: []string{url}, dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      []string{url, "asd"},
-			percent:   0,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parse color",
-			args:      args{part: "#ffffff", dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   0,
-			milestone: 0,
-			wantColor: &color.RGBA{R: 255, G: 255, B: 255, A: 255},
-		},
-		{
-			name:      "parsing color overwrites existing color",
-			args:      args{part: "#ffffff", c: &color.RGBA{R: 30, G: 20, B: 40, A: 30}, dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   0,
-			milestone: 0,
-			wantColor: &color.RGBA{R: 255, G: 255, B: 255, A: 255},
-		},
-		{
-			name:      "parse percentage",
-			args:      args{part: "60%", dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   60,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parsing percentage overwrites existing percentage",
-			args:      args{part: "60%", p: 30, dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   60,
-			milestone: 0,
-			wantColor: nil,
-		},
-		{
-			name:      "parse milestone",
-			args:      args{part: "|3", dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   0,
-			milestone: 3,
-			wantColor: nil,
-		},
-		{
-			name:      "parsing milestone overwrites existing milestone",
-			args:      args{part: "|3", m: 2, dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   0,
-			milestone: 3,
-			wantColor: nil,
-		},
-		{
-			name:      "fallback",
-			args:      args{part: "lkjsdflksd", m: 2, dateFormat: "2006-01-02", baseUrl: ""},
-			startAt:   nil,
-			endAt:     nil,
-			urls:      nil,
-			percent:   0,
-			milestone: 2,
-			wantColor: nil,
+			name: "parse from",
+			args: args{part: "2020-12-31", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{startAt: &nye},
+		},
+		{
+			name: "parse to",
+			args: args{part: "2020-12-31", e: extraData{startAt: &nye}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{startAt: &nye, endAt: &nye},
+		},
+		{
+			name:    "parsing to overwrites existing to",
+			args:    args{part: "2020-12-31", e: extraData{startAt: &nye, endAt: &now}, dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{startAt: &nye, endAt: &nye},
+			wantErr: errEndDateOverwritten,
+		},
+		{
+			name:    "invalid date",
+			args:    args{part: "2020-02-31", dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want:    extraData{},
+			wantErr: errInvalidDate,
+		},
+		{
+			name: "parse url",
+			args: args{part: url},
+			want: extraData{urls: []string{url}},
+		},
+		{
+			name: "parsing url overwrites existing url",
+			args: args{part: "asd", e: extraData{urls: []string{url}}, dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want: extraData{urls: []string{url, "asd"}},
+		},
+		{
+			name: "parse color",
+			args: args{part: "#ffffff", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{color: &color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		},
+		{
+			name: "parsing color overwrites existing color",
+			args: args{part: "#ffffff", e: extraData{color: &color.RGBA{R: 30, G: 20, B: 40, A: 30}}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{color: &color.RGBA{R: 255, G: 255, B: 255, A: 255}},
+		},
+		{
+			name:    "invalid color",
+			args:    args{part: "#ff00", dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want:    extraData{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "parse percentage",
+			args: args{part: "60%", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{percentage: 60},
+		},
+		{
+			name: "parsing percentage overwrites existing percentage",
+			args: args{part: "60%", e: extraData{percentage: 30}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{percentage: 60},
+		},
+		{
+			name:    "invalid percentage",
+			args:    args{part: "120%", e: extraData{percentage: 30}, dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want:    extraData{percentage: 30},
+			wantErr: errCannotParsePercentage,
+		},
+		{
+			name: "parse milestone",
+			args: args{part: "|3", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{milestone: 3},
+		},
+		{
+			name: "parsing milestone overwrites existing milestone",
+			args: args{part: "|3", e: extraData{milestone: 2}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{milestone: 3},
+		},
+		{
+			name: "parse status",
+			args: args{part: "blocked", dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want: extraData{status: StatusBlocked},
+		},
+		{
+			name: "parse owner",
+			args: args{part: "@peter", e: extraData{owners: []string{"anna"}}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{owners: []string{"anna", "peter"}},
+		},
+		{
+			name:    "empty owner",
+			args:    args{part: "@", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errCannotParseOwner,
+		},
+		{
+			name: "parse label",
+			args: args{part: "~backend", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{labels: []string{"backend"}},
+		},
+		{
+			name: "parse weight",
+			args: args{part: "weight=5", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{weight: 5},
+		},
+		{
+			name:    "invalid weight",
+			args:    args{part: "weight=-1", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errCannotParseWeight,
+		},
+		{
+			name:    "weight not a number",
+			args:    args{part: "weight=NaN", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errCannotParseWeight,
+		},
+		{
+			name:    "weight as field key",
+			args:    args{part: "weight=heavy", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errReservedWeightKey,
+		},
+		{
+			name: "parse estimate",
+			args: args{part: "estimate=8", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{estimate: 8},
+		},
+		{
+			name:    "estimate not a number",
+			args:    args{part: "estimate=NaN", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errCannotParseEstimate,
+		},
+		{
+			name: "parse field",
+			args: args{part: "cost=40k", e: extraData{fields: Fields{{Key: "team", Value: "payments"}}}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{fields: Fields{{Key: "team", Value: "payments"}, {Key: "cost", Value: "40k"}}},
+		},
+		{
+			name:    "parsing field overwrites existing field",
+			args:    args{part: "team=core", e: extraData{fields: Fields{{Key: "team", Value: "payments"}}}, dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{fields: Fields{{Key: "team", Value: "core"}}},
+			wantErr: errFieldOverwritten,
+		},
+		{
+			name: "parse milestone reference",
+			args: args{part: "|beta", e: extraData{milestone: 2}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{milestoneRef: "beta"},
+		},
+		{
+			name:    "empty milestone reference",
+			args:    args{part: "|", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errCannotParseMilestone,
+		},
+		{
+			name: "parse id",
+			args: args{part: "id:backend", dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want: extraData{id: "backend"},
+		},
+		{
+			name: "parse dependencies",
+			args: args{part: "after:Create prototype", e: extraData{dependsOn: []string{"backend"}}, dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want: extraData{dependsOn: []string{"backend", "Create prototype"}},
+		},
+		{
+			name:    "missing dependency reference",
+			args:    args{part: "after:", dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want:    extraData{},
+			wantErr: assert.AnError,
+		},
+		{
+			name: "parse relative start date",
+			args: args{part: "^+2w", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{startExpr: "^+2w"},
+		},
+		{
+			name:    "relative start date after start date",
+			args:    args{part: "+2w", e: extraData{startAt: &nye}, dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{startAt: &nye},
+			wantErr: errStartDateAlreadySet,
+		},
+		{
+			name: "parse to after relative start date",
+			args: args{part: "2020-12-31", e: extraData{startExpr: "+2w"}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{startExpr: "+2w", endAt: &nye},
+		},
+		{
+			name: "parse duration",
+			args: args{part: "10d", e: extraData{startAt: &nye}, dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want: extraData{startAt: &nye, endExpr: "10d"},
+		},
+		{
+			name:    "duration overwrites existing to",
+			args:    args{part: "3w", e: extraData{startAt: &nye, endAt: &now}, dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{startAt: &nye, endExpr: "3w"},
+			wantErr: errEndDateOverwritten,
+		},
+		{
+			name: "parse period",
+			args: args{part: "2021-Q3", dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{startExpr: "2021-Q3"},
+		},
+		{
+			name: "parse period after start date",
+			args: args{part: "2021-W14", e: extraData{startAt: &nye}, dateFormat: "2006-01-02", baseUrl: ""},
+			want: extraData{startAt: &nye, endExpr: "2021-W14"},
+		},
+		{
+			name:    "invalid period",
+			args:    args{part: "2021-Q5", dateFormat: "2006-01-02", baseUrl: "http://example.com/"},
+			want:    extraData{},
+			wantErr: errInvalidPeriod,
+		},
+		{
+			name:    "fallback",
+			args:    args{part: "lkjsdflksd", e: extraData{milestone: 2}, dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{milestone: 2},
+			wantErr: errUnknownExtra,
+		},
+		{
+			name:    "empty",
+			args:    args{part: "", dateFormat: "2006-01-02", baseUrl: ""},
+			want:    extraData{},
+			wantErr: errEmptyExtra,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			startAt, endAt, urls, gotColor, percent, milestone := parseExtraPart(tt.args.part, tt.args.f, tt.args.t, tt.args.u, tt.args.c, tt.args.p, tt.args.m, tt.args.dateFormat, tt.args.baseUrl)
-			if !reflect.DeepEqual(startAt, tt.startAt) {
-				t.Errorf("parseProjectExtra() startAt = %v, want %v", startAt, tt.startAt)
-			}
-			if !reflect.DeepEqual(endAt, tt.endAt) {
-				t.Errorf("parseProjectExtra() endAt = %v, want %v", endAt, tt.endAt)
-			}
-			if !reflect.DeepEqual(urls, tt.urls) {
-				t.Errorf("parseProjectExtra() urls = %v, want %v", urls, tt.urls)
-			}
-			if !reflect.DeepEqual(gotColor, tt.wantColor) {
-				t.Errorf("parseProjectExtra() gotColor = %v, want %v", gotColor, tt.wantColor)
-			}
-			if milestone != tt.milestone {
-				t.Errorf("parseProjectExtra() milestone = %v, want %v", milestone, tt.milestone)
+			got, err := parseExtraPart(tt.args.part, tt.args.e, tt.args.dateFormat, tt.args.baseUrl)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseExtraPart() got = %v, want %v", got, tt.want)
 			}
-			if percent != tt.percent {
-				t.Errorf("parseProjectExtra() percent = %v, want %v", percent, tt.percent)
+			switch tt.wantErr {
+			case nil:
+				assert.NoError(t, err)
+			case assert.AnError:
+				assert.Error(t, err)
+			default:
+				assert.True(t, errors.Is(err, tt.wantErr), "parseExtraPart() error = %v, want %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func Test_parseExtra(t *testing.T) {
+	var (
+		startAt = time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC)
+		endAt   = time.Date(2020, 2, 20, 0, 0, 0, 0, time.UTC)
+	)
+
+	type args struct {
+		extra      string
+		line       int
+		column     int
+		dateFormat string
+		baseUrl    string
+	}
+	tests := []struct {
+		name  string
+		args  args
+		want  extraData
+		want1 Diagnostics
+	}{
+		{
+			"empty",
+			args{"", 3, 0, "2006-01-02", ""},
+			extraData{},
+			nil,
+		},
+		{
+			"valid",
+			args{"2020-02-12, 2020-02-20, 30%", 3, 10, "2006-01-02", ""},
+			extraData{startAt: &startAt, endAt: &endAt, percentage: 30},
+			nil,
+		},
+		{
+			"invalid parts",
+			args{"2020-02-12, 2020-02-30, 120%, #ff00, foo", 3, 10, "2006-01-02", ""},
+			extraData{startAt: &startAt},
+			Diagnostics{
+				{Line: 3, Column: 22, Severity: SeverityError, Message: "invalid date, expected format: 2006-01-02", Token: "2020-02-30"},
+				{Line: 3, Column: 34, Severity: SeverityError, Message: "can not parse string as percentage (0-100%)", Token: "120%"},
+				{Line: 3, Column: 40, Severity: SeverityError, Message: "invalid hexa color length, expected format: #rgb or #rrggbb", Token: "#ff00"},
+				{Line: 3, Column: 47, Severity: SeverityWarning, Message: "unknown value, it will be ignored", Token: "foo"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := parseExtra(tt.args.extra, tt.args.line, tt.args.column, tt.args.dateFormat, tt.args.baseUrl)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+		})
+	}
+}
+
 func Test_parsePercentage(t *testing.T) {
 	type args struct {
 		part string
@@ -1314,3 +1673,57 @@ func Test_parseColor(t *testing.T) {
 		})
 	}
 }
+
+func TestRoadmap_validateDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []Project
+		want     Diagnostics
+	}{
+		{
+			"no dependencies",
+			[]Project{{Title: "foo"}, {Title: "bar"}},
+			nil,
+		},
+		{
+			"dependencies by id and title",
+			[]Project{{Title: "foo", ID: "f"}, {Title: "bar", DependsOn: []string{"f"}}, {Title: "baz", DependsOn: []string{"foo", "bar"}}},
+			nil,
+		},
+		{
+			"missing dependency",
+			[]Project{{Title: "foo"}, {Title: "bar", DependsOn: []string{"quix"}}},
+			Diagnostics{
+				{Line: 3, Column: 1, Severity: SeverityError, Message: "dependency not found", Token: "after:quix"},
+			},
+		},
+		{
+			"duplicate id",
+			[]Project{{Title: "foo", ID: "f"}, {Title: "bar", ID: "f"}},
+			Diagnostics{
+				{Line: 3, Column: 1, Severity: SeverityError, Message: "id is already used on line 2", Token: "id:f"},
+			},
+		},
+		{
+			"self dependency",
+			[]Project{{Title: "foo", DependsOn: []string{"foo"}}},
+			Diagnostics{
+				{Line: 2, Column: 1, Severity: SeverityError, Message: "circular dependency found", Token: "foo"},
+			},
+		},
+		{
+			"cycle is reported once",
+			[]Project{{Title: "foo", DependsOn: []string{"baz"}}, {Title: "bar", DependsOn: []string{"foo"}}, {Title: "baz", DependsOn: []string{"bar"}}},
+			Diagnostics{
+				{Line: 2, Column: 1, Severity: SeverityError, Message: "circular dependency found", Token: "foo"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Roadmap{Projects: tt.projects}
+			got := r.validateDependencies(locator{projectLines: []int{2, 3, 4}})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package roadmap
+
+import (
+	"errors"
+	"strings"
+)
+
+// Status represents the state of a project beyond its percentage
+type Status string
+
+const (
+	StatusPlanned    Status = "planned"
+	StatusInProgress Status = "in-progress"
+	StatusDone       Status = "done"
+	StatusAtRisk     Status = "at-risk"
+	StatusBlocked    Status = "blocked"
+	StatusCancelled  Status = "cancelled"
+)
+
+var errUnknownStatus = errors.New("unknown status, expected one of: planned, in-progress, done, at-risk, blocked, cancelled")
+
+// statusAliases contains alternative spellings accepted for statuses
+var statusAliases = map[string]Status{
+	"canceled": StatusCancelled,
+}
+
+// parseStatus tries to parse a string as a recognized status (e.g. blocked, at-risk), the case is ignored
+func parseStatus(part string) (Status, bool) {
+	part = strings.ToLower(part)
+
+	switch s := Status(part); s {
+	case StatusPlanned, StatusInProgress, StatusDone, StatusAtRisk, StatusBlocked, StatusCancelled:
+		return s, true
+	}
+
+	s, ok := statusAliases[part]
+
+	return s, ok
+}
@@ -0,0 +1,28 @@
+package roadmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		part  string
+		want  Status
+		want1 bool
+	}{
+		{"blocked", "blocked", StatusBlocked, true},
+		{"case is ignored", "At-Risk", StatusAtRisk, true},
+		{"alias", "canceled", StatusCancelled, true},
+		{"unknown", "paused", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := parseStatus(tt.part)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want1, got1)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package roadmap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// timeOfDayLayout is appended to the date format of a roadmap for dates with a time of day (e.g. 2020-02-01 14:30)
+const timeOfDayLayout = " 15:04"
+
+var errUnknownTimezone = errors.New("unknown timezone, expected a name from the IANA time zone database (e.g. Europe/Berlin)")
+
+// ParseTimezone tries to load a timezone by its name (e.g. Europe/Berlin), an empty name results in a nil location
+func ParseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errUnknownTimezone
+	}
+
+	return loc, nil
+}
+
+// parseDate parses a string as a date using the date format given, optionally followed by a time of day
+// dates and times are kept as they are written, they are interpreted in the timezone of the roadmap, see wallClock
+func parseDate(dateFormat, s string) (time.Time, error) {
+	t, err := time.Parse(dateFormat, s)
+	if err == nil {
+		return t, nil
+	}
+
+	return time.Parse(dateFormat+timeOfDayLayout, s)
+}
+
+// formatDate converts a date into a string using the date format given, the time of day is only added if it is set
+func formatDate(t time.Time, dateFormat string) string {
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format(dateFormat)
+	}
+
+	return t.Format(dateFormat + timeOfDayLayout)
+}
+
+// wallClock returns the date and time shown on a clock in the location given at the time given
+// dates of roadmaps are stored without a timezone, therefore times have to be converted before comparing them
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t = t.In(loc)
+
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// ParseReferenceDate parses the date used instead of the current day when marking today (e.g. 2020-03-01)
+// the date format of the roadmap and the default date format are both accepted, an empty string results in a zero time
+func ParseReferenceDate(s, dateFormat string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, f := range []string{dateFormat, defaultDateFormat} {
+		if t, err := parseDate(f, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w, expected format: %s", errInvalidDate, dateFormat)
+}
+
+// ApplyTimezone sets the timezone used for calculating the current day, nil keeps the timezone of the roadmap
+func (vr *VisualRoadmap) ApplyTimezone(loc *time.Location) *VisualRoadmap {
+	if loc != nil {
+		vr.Timezone = loc
+	}
+
+	return vr
+}
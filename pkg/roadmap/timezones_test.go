@@ -0,0 +1,108 @@
+package roadmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	tests := []struct {
+		name    string
+		tz      string
+		want    *time.Location
+		wantErr error
+	}{
+		{"empty", "", nil, nil},
+		{"known", "Europe/Berlin", berlin, nil},
+		{"unknown", "Mars/Olympus_Mons", nil, errUnknownTimezone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimezone(tt.tz)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		dateFormat string
+		s          string
+		want       time.Time
+		wantErr    bool
+	}{
+		{"date", "2006-01-02", "2020-02-01", time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), false},
+		{"date with time of day", "2006-01-02", "2020-02-01 14:30", time.Date(2020, 2, 1, 14, 30, 0, 0, time.UTC), false},
+		{"other date format with time of day", "02.01.2006", "01.02.2020 9:05", time.Date(2020, 2, 1, 9, 5, 0, 0, time.UTC), false},
+		{"invalid time of day", "2006-01-02", "2020-02-01 25:00", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.dateFormat, tt.s)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_formatDate(t *testing.T) {
+	assert.Equal(t, "2020-02-01", formatDate(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), "2006-01-02"))
+	assert.Equal(t, "2020-02-01 14:30", formatDate(time.Date(2020, 2, 1, 14, 30, 0, 0, time.UTC), "2006-01-02"))
+}
+
+func Test_wallClock(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	now := time.Date(2020, 2, 1, 20, 30, 15, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2020, 2, 1, 20, 30, 0, 0, time.UTC), wallClock(now, nil))
+	assert.Equal(t, time.Date(2020, 2, 2, 5, 30, 0, 0, time.UTC), wallClock(now, tokyo))
+}
+
+func TestContent_Parse_timeOfDay(t *testing.T) {
+	c := Content("Project [2020-02-01 09:00, 2020-02-01 17:30]\n\tPhases [Design:2020-02-01 09:00..2020-02-01 12:00]\n\n|Release [2020-02-01 18:00]")
+
+	r, ds := c.Parse(0, nil, "", "2006-01-02", "", time.Now())
+
+	assert.Empty(t, ds)
+	assert.Equal(t, &Dates{StartAt: time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 1, 17, 30, 0, 0, time.UTC)}, r.Projects[0].Dates)
+	assert.Equal(t, []Phase{{Title: "Design", Dates: Dates{StartAt: time.Date(2020, 2, 1, 9, 0, 0, 0, time.UTC), EndAt: time.Date(2020, 2, 1, 12, 0, 0, 0, time.UTC)}}}, r.Projects[1].Phases)
+	assert.Equal(t, c, r.ToContent())
+}
+
+func TestParseReferenceDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		dateFormat string
+		want       time.Time
+		wantErr    bool
+	}{
+		{"empty", "", "02.01.2006", time.Time{}, false},
+		{"date format of the roadmap", "01.03.2020", "02.01.2006", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"default date format", "2020-03-01", "02.01.2006", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"time of day", "2020-03-01 12:00", "2006-01-02", time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC), false},
+		{"invalid", "yesterday", "2006-01-02", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReferenceDate(tt.s, tt.dateFormat)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package roadmap
+
+import (
+	"fmt"
+
+	"github.com/peteraba/roadmapper/pkg/colors"
+)
+
+// Validate checks a roadmap which was not read from text (e.g. received via the JSON API) the way Parse checks a
+// Content, diagnostics refer to the values affected by their path (e.g. projects[2].percentage)
+// relative dates are calculated as a side effect, the same way Parse does
+func (r Roadmap) Validate() Diagnostics {
+	var ds Diagnostics
+
+	ds = append(ds, r.validateSettings()...)
+
+	if len(r.Projects) > 0 && r.Projects[0].Indentation > 0 {
+		ds = append(ds, Diagnostic{Path: projectPath(0) + ".indentation", Severity: SeverityError, Message: errFirstProjectIndented.Error()})
+	}
+
+	for i, p := range r.Projects {
+		ds = append(ds, p.validate(projectPath(i))...)
+	}
+
+	for i, m := range r.Milestones {
+		ds = append(ds, validateFields(m.Fields, milestonePath(i))...)
+	}
+
+	loc := locator{byPath: true}
+	ds = append(ds, r.validateMilestoneReferences(loc)...)
+	ds = append(ds, r.validateDependencies(loc)...)
+	ds = append(ds, r.resolveRelativeDates(loc)...)
+
+	return ds
+}
+
+// validateSettings checks the settings of a roadmap which could also be set in the header of a Content
+func (r Roadmap) validateSettings() Diagnostics {
+	var ds Diagnostics
+
+	if r.Theme != "" {
+		if _, err := colors.NewTheme(r.Theme); err != nil {
+			ds = append(ds, pathDiagnostic("theme", err, r.Theme))
+		}
+	}
+
+	if r.Progress != "" {
+		if _, err := parseProgressStrategy(string(r.Progress)); err != nil {
+			ds = append(ds, pathDiagnostic("progress", err, string(r.Progress)))
+		}
+	}
+
+	if r.Timezone != "" {
+		if _, err := ParseTimezone(r.Timezone); err != nil {
+			ds = append(ds, pathDiagnostic("timezone", err, r.Timezone))
+		}
+	}
+
+	return ds
+}
+
+// validate checks the values of a project which would be rejected if they were found in a Content
+func (p Project) validate(path string) Diagnostics {
+	var ds Diagnostics
+
+	add := func(field string, err error, token string) {
+		ds = append(ds, pathDiagnostic(path+"."+field, err, token))
+	}
+
+	if p.Dates != nil && !p.hasDateExpressions() && p.Dates.EndAt.Before(p.Dates.StartAt) {
+		ds = append(ds, Diagnostic{Path: path + ".dates", Severity: SeverityError, Message: "end date is before start date"})
+	}
+
+	for i, ph := range p.Phases {
+		if ph.Dates.EndAt.Before(ph.Dates.StartAt) {
+			ds = append(ds, Diagnostic{Path: fmt.Sprintf("%s.phases[%d]", path, i), Severity: SeverityError, Message: "end date of phase is before its start date", Token: ph.Title})
+		}
+	}
+
+	if p.Percentage > 100 {
+		add("percentage", errCannotParsePercentage, fmt.Sprintf("%d%%", p.Percentage))
+	}
+
+	if p.Weight != 0 {
+		if _, err := parseWeight(formatWeight(p.Weight)); err != nil {
+			add("weight", err, formatWeight(p.Weight))
+		}
+	}
+
+	if p.Estimate != 0 {
+		if _, err := parseEstimate(formatEstimate(p.Estimate)); err != nil {
+			add("estimate", err, formatEstimate(p.Estimate))
+		}
+	}
+
+	if p.Status != "" {
+		if _, ok := parseStatus(string(p.Status)); !ok {
+			add("status", errUnknownStatus, string(p.Status))
+		}
+	}
+
+	for i, o := range p.Owners {
+		if _, err := parseOwner(ownerPrefix + o); err != nil {
+			add(fmt.Sprintf("owners[%d]", i), err, ownerPrefix+o)
+		}
+	}
+
+	for i, l := range p.Labels {
+		if _, err := parseLabel(labelPrefix + l); err != nil {
+			add(fmt.Sprintf("labels[%d]", i), err, labelPrefix+l)
+		}
+	}
+
+	if p.Include != "" {
+		if _, err := parseInclude(includePrefix + p.Include); err != nil {
+			add("include", err, includePrefix+p.Include)
+		}
+	}
+
+	return append(ds, validateFields(p.Fields, path)...)
+}
+
+// validateFields checks custom fields, keys reserved for other values are reported the same way parsing reports them
+func validateFields(fields Fields, path string) Diagnostics {
+	var ds Diagnostics
+
+	for _, f := range fields {
+		fieldPath := path + ".fields." + f.Key
+
+		if _, err := parseField(f.String()); err != nil {
+			ds = append(ds, pathDiagnostic(fieldPath, err, f.String()))
+			continue
+		}
+
+		switch f.Key + fieldSeparator {
+		case weightPrefix:
+			ds = append(ds, pathDiagnostic(fieldPath, errReservedWeightKey, f.String()))
+		case estimatePrefix:
+			ds = append(ds, pathDiagnostic(fieldPath, errReservedEstimateKey, f.String()))
+		}
+	}
+
+	return ds
+}
+
+// pathDiagnostic creates a Diagnostic referring to a value by its path (e.g. projects[2].status)
+func pathDiagnostic(path string, err error, token string) Diagnostic {
+	d := newDiagnostic(0, 0, err, token)
+	d.Path = path
+
+	return d
+}
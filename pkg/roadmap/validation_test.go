@@ -0,0 +1,99 @@
+package roadmap
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoadmap_Validate(t *testing.T) {
+	var (
+		d1 = time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)
+		d2 = time.Date(2020, 2, 14, 0, 0, 0, 0, time.UTC)
+	)
+
+	tests := []struct {
+		name string
+		r    Roadmap
+		want Diagnostics
+	}{
+		{
+			"valid",
+			Roadmap{
+				DateFormat: "2006-01-02",
+				Theme:      "cool",
+				Projects: []Project{
+					{Title: "Backend", ID: "be", Dates: &Dates{StartAt: d1, EndAt: d2}, Percentage: 40, Owners: []string{"peter"}, Labels: []string{"api"}, Fields: Fields{{Key: "team", Value: "payments"}}},
+					{Title: "Frontend", StartExpression: "+1d", EndExpression: "1w", DependsOn: []string{"be"}, MilestoneRef: "beta"},
+				},
+				Milestones: []Milestone{{Title: "Beta", Key: "beta"}},
+			},
+			nil,
+		},
+		{
+			"settings",
+			Roadmap{Theme: "neon", Progress: "random", Timezone: "Mars/Olympus"},
+			Diagnostics{
+				{Path: "theme", Severity: SeverityError, Message: "unknown theme, supported themes: default, cool, warm, mono", Token: "neon"},
+				{Path: "progress", Severity: SeverityError, Message: errUnknownProgressStrategy.Error(), Token: "random"},
+				{Path: "timezone", Severity: SeverityError, Message: errUnknownTimezone.Error(), Token: "Mars/Olympus"},
+			},
+		},
+		{
+			"projects",
+			Roadmap{
+				Projects: []Project{
+					{Title: "Backend", Dates: &Dates{StartAt: d2, EndAt: d1}, Percentage: 120, Weight: -1, Status: "paused"},
+					{Title: "Frontend", Owners: []string{"peter pan"}, Labels: []string{""}, Fields: Fields{{Key: "estimate", Value: "large"}}, Include: "!"},
+					{Title: "Design", Weight: math.NaN(), Estimate: math.NaN()},
+				},
+			},
+			Diagnostics{
+				{Path: "projects[0].dates", Severity: SeverityError, Message: "end date is before start date"},
+				{Path: "projects[0].percentage", Severity: SeverityError, Message: errCannotParsePercentage.Error(), Token: "120%"},
+				{Path: "projects[0].weight", Severity: SeverityError, Message: errCannotParseWeight.Error(), Token: "weight=-1"},
+				{Path: "projects[0].status", Severity: SeverityError, Message: errUnknownStatus.Error(), Token: "paused"},
+				{Path: "projects[1].owners[0]", Severity: SeverityError, Message: errCannotParseOwner.Error(), Token: "@peter pan"},
+				{Path: "projects[1].labels[0]", Severity: SeverityError, Message: errCannotParseLabel.Error(), Token: "~"},
+				{Path: "projects[1].include", Severity: SeverityError, Message: errCannotParseInclude.Error(), Token: "include:!"},
+				{Path: "projects[1].fields.estimate", Severity: SeverityError, Message: errReservedEstimateKey.Error(), Token: "estimate=large"},
+				{Path: "projects[2].weight", Severity: SeverityError, Message: errCannotParseWeight.Error(), Token: "weight=NaN"},
+				{Path: "projects[2].estimate", Severity: SeverityError, Message: errCannotParseEstimate.Error(), Token: "estimate=NaN"},
+			},
+		},
+		{
+			"first project is indented",
+			Roadmap{
+				Projects: []Project{
+					{Title: "Backend", Indentation: 1},
+				},
+			},
+			Diagnostics{
+				{Path: "projects[0].indentation", Severity: SeverityError, Message: errFirstProjectIndented.Error()},
+			},
+		},
+		{
+			"references",
+			Roadmap{
+				Projects: []Project{
+					{Title: "Backend", ID: "be", MilestoneRef: "beta"},
+					{Title: "Frontend", ID: "be", DependsOn: []string{"api"}},
+				},
+				Milestones: []Milestone{{Title: "Alpha", Key: "a"}, {Title: "Beta", Key: "a"}},
+			},
+			Diagnostics{
+				{Path: "milestones[1]", Severity: SeverityError, Message: "id is already used on milestones[0]", Token: "id:a"},
+				{Path: "projects[0]", Severity: SeverityError, Message: "milestone beta does not exist", Token: "|beta"},
+				{Path: "projects[1]", Severity: SeverityError, Message: "id is already used on projects[0]", Token: "id:be"},
+				{Path: "projects[1]", Severity: SeverityError, Message: "dependency not found", Token: "after:api"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.r.Validate())
+		})
+	}
+}
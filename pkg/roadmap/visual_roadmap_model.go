@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/peteraba/roadmapper/pkg/code"
 	"github.com/peteraba/roadmapper/pkg/colors"
 )
 
@@ -16,6 +17,22 @@ type VisualRoadmap struct {
 	Milestones []Milestone
 	Dates      *Dates
 	DateFormat string
+	Progress   ProgressStrategy
+	// TitleTemplate is used for displaying the titles of projects and milestones, see expandTitleTemplate
+	TitleTemplate string
+	// Timezone is used for calculating the current day, UTC if nil
+	Timezone *time.Location
+	// Theme sets the color palettes used for projects without a color
+	Theme colors.Theme
+	// Code is the code of the roadmap, used for deriving the UIDs of calendar events, see ApplyCalendarCode
+	Code string
+	// CalendarProjects sets whether projects are exported as calendar events, see ToICS
+	CalendarProjects bool
+
+	// origins holds the index of the project each row was created from if the projects were rearranged, see toSwimlanes
+	origins []int
+	// dependencies holds the rows each row depends on, see resolveDependencies
+	dependencies [][]int
 }
 
 // ToVisual converts a roadmap to a visual roadmap
@@ -24,13 +41,22 @@ type VisualRoadmap struct {
 func (r Roadmap) ToVisual() *VisualRoadmap {
 	visual := &VisualRoadmap{}
 
+	// roadmaps created via the API might contain relative dates without calculated dates
+	resolveDateExpressions(r.Projects, r.DateFormat)
+
 	visual.Title = r.Title
 	visual.Dates = r.ToDates()
-	visual.Projects = r.Projects
+	// projects are copied so that calculated values (e.g. estimates) don't end up in the roadmap
+	visual.Projects = append([]Project(nil), r.Projects...)
+	visual.dependencies = resolveDependencies(visual.Projects)
 	visual.Milestones = r.Milestones
 	visual.DateFormat = r.DateFormat
+	visual.Progress = r.Progress
+	visual.Timezone, _ = ParseTimezone(r.Timezone)
+	visual.Theme, _ = colors.NewTheme(r.Theme)
+	visual.Code = code.Uint64ToString(r.ID)
 
-	visual.calculateProjectDates().calculateProjectColors().calculatePercentages().applyBaseURL(r.BaseURL)
+	visual.calculateProjectDates().calculateProjectColors().calculatePercentages().calculateEstimates().applyBaseURL(r.BaseURL)
 
 	projectMilestones := visual.collectProjectMilestones()
 	visual.applyProjectMilestone(projectMilestones)
@@ -38,6 +64,56 @@ func (r Roadmap) ToVisual() *VisualRoadmap {
 	return visual
 }
 
+// resolveDependencies returns the indexes of the projects each project depends on, nil if there are no dependencies
+// dependencies are resolved once, so that they keep pointing to the right rows when projects are filtered or rearranged
+func resolveDependencies(projects []Project) [][]int {
+	var deps [][]int
+
+	for i, p := range projects {
+		for _, ref := range p.DependsOn {
+			j := findDependency(projects, i, ref)
+			if j < 0 || j == i {
+				continue
+			}
+
+			if deps == nil {
+				deps = make([][]int, len(projects))
+			}
+
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	return deps
+}
+
+// findDependency returns the index of the project a dependency of the project at index i refers to, -1 if not found
+// dependencies only point to projects of the same roadmap, never to projects of the same title included from another one
+func findDependency(projects []Project, i int, ref string) int {
+	for j, p := range projects {
+		if p.IncludedFrom == projects[i].IncludedFrom && p.ID == ref {
+			return j
+		}
+	}
+
+	for j, p := range projects {
+		if p.IncludedFrom == projects[i].IncludedFrom && p.Title == ref {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// dependenciesOf returns the rows the project displayed in a row depends on
+func (vr *VisualRoadmap) dependenciesOf(row int) []int {
+	if row >= len(vr.dependencies) {
+		return nil
+	}
+
+	return vr.dependencies[row]
+}
+
 // calculateProjectDates tries to find reasonable dates for all projects
 // first it tries to find dates bottom up, meaning that based on the sub-projects
 // then it tries to find dates top down, meaning that it will copy over dates from parents
@@ -45,7 +121,8 @@ func (vr *VisualRoadmap) calculateProjectDates() *VisualRoadmap {
 	for i := range vr.Projects {
 		p := &vr.Projects[i]
 
-		if p.Dates != nil {
+		// projects with phases need to span over all of their phases
+		if p.Dates != nil && len(p.Phases) == 0 {
 			continue
 		}
 
@@ -71,8 +148,8 @@ func (vr *VisualRoadmap) findDatesBottomUp(start int) *Dates {
 		panic(fmt.Errorf("illegal start %d for finding visual dates", start))
 	}
 
-	if vr.Projects[start].Dates != nil {
-		return vr.Projects[start].Dates
+	if span := vr.Projects[start].span(); span != nil {
+		return span
 	}
 
 	minIndentation := vr.Projects[start].Indentation + 1
@@ -84,22 +161,12 @@ func (vr *VisualRoadmap) findDatesBottomUp(start int) *Dates {
 			break
 		}
 
-		if p.Dates == nil {
+		span := p.span()
+		if span == nil {
 			continue
 		}
 
-		if dates == nil {
-			dates = &Dates{StartAt: p.Dates.StartAt, EndAt: p.Dates.EndAt}
-			continue
-		}
-
-		if dates.StartAt.After(p.Dates.StartAt) {
-			dates.StartAt = p.Dates.StartAt
-		}
-
-		if dates.EndAt.Before(p.Dates.EndAt) {
-			dates.EndAt = p.Dates.EndAt
-		}
+		dates = extendDates(dates, *span)
 	}
 
 	return dates
@@ -141,7 +208,8 @@ func (vr *VisualRoadmap) calculateProjectColors() *VisualRoadmap {
 	for i := range vr.Projects {
 		p := &vr.Projects[i]
 
-		if p.Indentation == 0 {
+		// projects preceding the first top-level project (e.g. filtered out) are colored as part of the first one
+		if p.Indentation == 0 || epicCount < 0 {
 			epicCount++
 			taskCount = -1
 		}
@@ -149,7 +217,7 @@ func (vr *VisualRoadmap) calculateProjectColors() *VisualRoadmap {
 
 		c := p.Color
 		if c == nil {
-			c = colors.PickFgColor(epicCount, taskCount, int(p.Indentation))
+			c = vr.Theme.PickFgColor(epicCount, taskCount, int(p.Indentation))
 		}
 
 		p.Color = c
@@ -174,7 +242,7 @@ func (vr *VisualRoadmap) calculatePercentages() *VisualRoadmap {
 	return vr
 }
 
-// findPercentageBottomUp will calculate the average percentage of sub-projects
+// findPercentageBottomUp will calculate the weighted average percentage of sub-projects, see ProgressStrategy
 func (vr *VisualRoadmap) findPercentageBottomUp(start int) uint8 {
 	if vr.Projects == nil || len(vr.Projects) < start {
 		panic(fmt.Errorf("illegal start %d for finding visual dates", start))
@@ -186,7 +254,8 @@ func (vr *VisualRoadmap) findPercentageBottomUp(start int) uint8 {
 
 	matchIndentation := vr.Projects[start].Indentation + 1
 
-	var sum, count uint8
+	// float64 is used so that sums can not overflow
+	var sum, weights, plainSum, count float64
 	for i := start + 1; i < len(vr.Projects); i++ {
 		p := &vr.Projects[i]
 		if p.Indentation < matchIndentation {
@@ -201,7 +270,10 @@ func (vr *VisualRoadmap) findPercentageBottomUp(start int) uint8 {
 			p.Percentage = vr.findPercentageBottomUp(i)
 		}
 
-		sum += p.Percentage
+		w := vr.progressWeight(*p)
+		sum += float64(p.Percentage) * w
+		weights += w
+		plainSum += float64(p.Percentage)
 		count++
 	}
 
@@ -209,7 +281,12 @@ func (vr *VisualRoadmap) findPercentageBottomUp(start int) uint8 {
 		return 0
 	}
 
-	return sum / count
+	// if no sub-project has a weight (e.g. none of them has dates), all of them count the same
+	if weights == 0 {
+		return uint8(plainSum / count)
+	}
+
+	return uint8(sum / weights)
 }
 
 func (vr *VisualRoadmap) applyBaseURL(baseUrl string) *VisualRoadmap {
@@ -258,7 +335,8 @@ func (vr *VisualRoadmap) collectProjectMilestones() map[int]*Milestone {
 	for i := range vr.Projects {
 		p := &vr.Projects[i]
 
-		if p.Milestone == 0 {
+		mk := findMilestone(vr.Milestones, *p)
+		if mk < 0 {
 			continue
 		}
 
@@ -266,8 +344,6 @@ func (vr *VisualRoadmap) collectProjectMilestones() map[int]*Milestone {
 			continue
 		}
 
-		mk := int(p.Milestone) - 1
-
 		var endAt *time.Time
 		if p.Dates != nil {
 			endAt = &p.Dates.EndAt
@@ -300,8 +376,9 @@ func (vr *VisualRoadmap) collectProjectMilestones() map[int]*Milestone {
 // found or generated for the projects linked to a milestone
 func (vr *VisualRoadmap) applyProjectMilestone(projectMilestones map[int]*Milestone) *VisualRoadmap {
 	for i, m := range projectMilestones {
-		if len(vr.Milestones) < i {
-			panic("original milestone not found")
+		// dangling references are reported during validation, they are simply ignored here
+		if i < 0 || i >= len(vr.Milestones) {
+			continue
 		}
 
 		om := &vr.Milestones[i]
@@ -329,3 +406,32 @@ func (vr *VisualRoadmap) applyProjectMilestone(projectMilestones map[int]*Milest
 
 	return vr
 }
+
+// headerLabels returns the labels to be used for the start and the end of a visual roadmap
+// if the roadmap starts or ends with a project defined by a period (e.g. 2021-Q3, 2021-W14), the period is used as
+// written by the user, otherwise the dates are formatted using the date format of the roadmap
+func (vr *VisualRoadmap) headerLabels() (string, string) {
+	startLabel := vr.Dates.StartAt.Format(vr.DateFormat)
+	endLabel := vr.Dates.EndAt.Format(vr.DateFormat)
+
+	for _, p := range vr.Projects {
+		if p.Dates == nil {
+			continue
+		}
+
+		if isPeriod(p.StartExpression) && p.Dates.StartAt.Equal(vr.Dates.StartAt) {
+			startLabel = p.StartExpression
+		}
+
+		endExpression := p.EndExpression
+		if endExpression == "" {
+			endExpression = p.StartExpression
+		}
+
+		if isPeriod(endExpression) && p.Dates.EndAt.Equal(vr.Dates.EndAt) {
+			endLabel = endExpression
+		}
+	}
+
+	return startLabel, endLabel
+}
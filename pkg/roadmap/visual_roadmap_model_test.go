@@ -64,7 +64,7 @@ func TestRoadmap_ToVisual(t *testing.T) {
 		{
 			"empty",
 			fields{CreatedAt: dates0402, UpdatedAt: dates0402, AccessedAt: dates0402},
-			&VisualRoadmap{},
+			&VisualRoadmap{Theme: colors.DefaultTheme},
 		},
 		{
 			"complex",
@@ -92,6 +92,8 @@ func TestRoadmap_ToVisual(t *testing.T) {
 			},
 			&VisualRoadmap{
 				DateFormat: "02.01.2006",
+				Code:       "1X",
+				Theme:      colors.DefaultTheme,
 				Projects: []Project{
 					{Title: "Initial development", Dates: &Dates{StartAt: dates0402, EndAt: dates0405}, URLs: urls1, Color: color3},
 					{Title: "Bring website online", Dates: &Dates{StartAt: dates0402, EndAt: dates0418}, Color: color1, Milestone: 1},
@@ -524,6 +526,21 @@ func TestVisualRoadmap_calculateProjectColors(t *testing.T) {
 				},
 			},
 		},
+		{
+			"first project is indented",
+			fields{
+				Projects: []Project{
+					{Title: "Select and purchase domain", Indentation: 2},
+					{Title: "Create server infrastructure", Indentation: 1},
+				},
+			},
+			&VisualRoadmap{
+				Projects: []Project{
+					{Title: "Select and purchase domain", Indentation: 2, Color: colors.PickFgColor(0, 0, 2)},
+					{Title: "Create server infrastructure", Indentation: 1, Color: color4},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -809,6 +826,7 @@ func TestVisualRoadmap_collectProjectMilestones(t *testing.T) {
 		{
 			"project with milestones are found",
 			fields{
+				Milestones: []Milestone{{Title: "m1"}},
 				Projects: []Project{
 					{Milestone: 1, Dates: &Dates{StartAt: dates0402, EndAt: dates0418}},
 					{Milestone: 1, Dates: &Dates{StartAt: dates0402, EndAt: dates0405}},
@@ -821,6 +839,7 @@ func TestVisualRoadmap_collectProjectMilestones(t *testing.T) {
 		{
 			"project sets deadline of milestone if later",
 			fields{
+				Milestones: []Milestone{{Title: "m1"}},
 				Projects: []Project{
 					{Milestone: 1, Dates: &Dates{StartAt: dates0402, EndAt: dates0405}},
 					{Milestone: 1, Dates: &Dates{StartAt: dates0402, EndAt: dates0418}},
@@ -833,6 +852,7 @@ func TestVisualRoadmap_collectProjectMilestones(t *testing.T) {
 		{
 			"latest project is used for a given milestone",
 			fields{
+				Milestones: []Milestone{{Title: "m1"}},
 				Projects: []Project{
 					{Milestone: 1},
 					{Milestone: 1, Dates: &Dates{StartAt: dates0408, EndAt: dates0415}},
@@ -847,6 +867,7 @@ func TestVisualRoadmap_collectProjectMilestones(t *testing.T) {
 		{
 			"projects with colors are not skipped",
 			fields{
+				Milestones: []Milestone{{Title: "m1"}, {Title: "m2"}},
 				Projects: []Project{
 					{Milestone: 1},
 					{Milestone: 1, Dates: &Dates{StartAt: dates0402, EndAt: dates0415}},
@@ -862,6 +883,7 @@ func TestVisualRoadmap_collectProjectMilestones(t *testing.T) {
 		{
 			"color of the first found project is used",
 			fields{
+				Milestones: []Milestone{{Title: "m1"}, {Title: "m2"}},
 				Projects: []Project{
 					{Milestone: 1},
 					{Milestone: 1, Dates: &Dates{StartAt: dates0402, EndAt: dates0415}, Color: color2, Indentation: 1},
@@ -874,6 +896,31 @@ func TestVisualRoadmap_collectProjectMilestones(t *testing.T) {
 				1: {Color: color1},
 			},
 		},
+		{
+			"milestones are found by key or title",
+			fields{
+				Milestones: []Milestone{{Title: "m1", Key: "beta"}, {Title: "m2"}},
+				Projects: []Project{
+					{MilestoneRef: "m2", Color: color1},
+					{MilestoneRef: "beta", Dates: &Dates{StartAt: dates0402, EndAt: dates0418}},
+				},
+			},
+			map[int]*Milestone{
+				0: {DeadlineAt: &dates0418},
+				1: {Color: color1},
+			},
+		},
+		{
+			"dangling references are skipped",
+			fields{
+				Milestones: []Milestone{{Title: "m1"}},
+				Projects: []Project{
+					{Milestone: 2, Color: color1},
+					{MilestoneRef: "beta", Dates: &Dates{StartAt: dates0402, EndAt: dates0418}},
+				},
+			},
+			map[int]*Milestone{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1038,19 +1085,103 @@ func TestVisualRoadmap_applyProjectMilestone(t *testing.T) {
 		})
 	}
 
-	t.Run("panic original milestone does not exist", func(t *testing.T) {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Errorf("the code did not panic")
-			}
-		}()
-
+	t.Run("missing original milestones are skipped", func(t *testing.T) {
 		milestone1 := &Milestone{}
 		projectMilestones := map[int]*Milestone{
 			1: milestone1,
 		}
 
 		vr := &VisualRoadmap{}
-		_ = vr.applyProjectMilestone(projectMilestones)
+		got := vr.applyProjectMilestone(projectMilestones)
+
+		if !reflect.DeepEqual(got, &VisualRoadmap{}) {
+			t.Errorf("applyProjectMilestone() = %v, want %v", got, &VisualRoadmap{})
+		}
 	})
 }
+
+func TestVisualRoadmap_headerLabels(t *testing.T) {
+	var (
+		d = func(month time.Month, day int) time.Time {
+			return time.Date(2021, month, day, 0, 0, 0, 0, time.UTC)
+		}
+	)
+
+	tests := []struct {
+		name     string
+		projects []Project
+		dates    *Dates
+		want     string
+		want1    string
+	}{
+		{
+			"dates",
+			[]Project{
+				{Dates: &Dates{StartAt: d(7, 1), EndAt: d(9, 30)}},
+			},
+			&Dates{StartAt: d(7, 1), EndAt: d(9, 30)},
+			"2021-07-01",
+			"2021-09-30",
+		},
+		{
+			"quarter used for start and end",
+			[]Project{
+				{StartExpression: "2021-Q3", Dates: &Dates{StartAt: d(7, 1), EndAt: d(9, 30)}},
+			},
+			&Dates{StartAt: d(7, 1), EndAt: d(9, 30)},
+			"2021-Q3",
+			"2021-Q3",
+		},
+		{
+			"week used for start, month for end",
+			[]Project{
+				{StartExpression: "2021-W27", EndExpression: "2021-07-20", Dates: &Dates{StartAt: d(7, 5), EndAt: d(7, 20)}},
+				{StartExpression: "2021-07-10", EndExpression: "2021-08", Dates: &Dates{StartAt: d(7, 10), EndAt: d(8, 31)}},
+			},
+			&Dates{StartAt: d(7, 5), EndAt: d(8, 31)},
+			"2021-W27",
+			"2021-08",
+		},
+		{
+			"periods not at the boundaries are ignored",
+			[]Project{
+				{StartExpression: "2021-W27", Dates: &Dates{StartAt: d(7, 5), EndAt: d(7, 11)}},
+			},
+			&Dates{StartAt: d(7, 1), EndAt: d(9, 30)},
+			"2021-07-01",
+			"2021-09-30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vr := &VisualRoadmap{
+				Projects:   tt.projects,
+				Dates:      tt.dates,
+				DateFormat: "2006-01-02",
+			}
+			got, got1 := vr.headerLabels()
+			if got != tt.want {
+				t.Errorf("headerLabels() got = %v, want %v", got, tt.want)
+			}
+			if got1 != tt.want1 {
+				t.Errorf("headerLabels() got1 = %v, want %v", got1, tt.want1)
+			}
+		})
+	}
+}
+
+func TestRoadmap_ToVisual_theme(t *testing.T) {
+	r := Roadmap{Theme: "mono", Projects: []Project{{Title: "Backend"}, {Title: "Frontend"}}}
+
+	vr := r.ToVisual()
+
+	if vr.Theme != colors.MonoTheme {
+		t.Errorf("ToVisual().Theme = %v, want %v", vr.Theme, colors.MonoTheme)
+	}
+
+	for i, p := range vr.Projects {
+		if want := colors.MonoTheme.PickFgColor(i, 0, 0); !reflect.DeepEqual(p.Color, want) {
+			t.Errorf("ToVisual().Projects[%d].Color = %v, want %v", i, p.Color, want)
+		}
+	}
+}
@@ -42,6 +42,7 @@ func NewServer(handler *roadmap.Handler, assetsDir, certFile, keyFile string) *S
 
 	e.GET("/", handler.GetRoadmapHTML)
 	e.POST("/", handler.CreateRoadmapHTML)
+	e.GET("/:identifier/csv", handler.GetRoadmapCSV)
 	e.GET("/:identifier/:format", handler.GetRoadmapImage)
 	e.GET("/:identifier", handler.GetRoadmapHTML)
 	e.POST("/:identifier", handler.CreateRoadmapHTML)
@@ -51,6 +52,7 @@ func NewServer(handler *roadmap.Handler, assetsDir, certFile, keyFile string) *S
 	roadmapsGroup := apiGroup.Group("/roadmaps")
 	roadmapsGroup.GET("/:identifier", handler.GetRoadmapJSON)
 	roadmapsGroup.POST("", handler.CreateRoadmapJSON)
+	roadmapsGroup.POST("/mermaid", handler.CreateRoadmapMermaid)
 
 	return &Server{
 		echo:      e,
@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE "roadmaps" ADD COLUMN "comments" jsonb;
+
+-- +migrate Down
+
+ALTER TABLE "roadmaps" DROP COLUMN "comments";
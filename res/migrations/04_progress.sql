@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE "roadmaps" ADD COLUMN "progress" text NOT NULL DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE "roadmaps" DROP COLUMN "progress";
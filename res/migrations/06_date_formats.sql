@@ -0,0 +1,10 @@
+-- +migrate Up
+
+UPDATE "roadmaps" SET "date_format" = '01/02/2006' WHERE "date_format" = '01/02/2020';
+UPDATE "roadmaps" SET "date_format" = '01.02.2006' WHERE "date_format" = '01.02.2020';
+UPDATE "roadmaps" SET "date_format" = '1/2/2006' WHERE "date_format" = '1/2/2020';
+UPDATE "roadmaps" SET "date_format" = '1.2.2006' WHERE "date_format" = '1.2.2020';
+
+-- +migrate Down
+
+-- legacy date formats can not be told apart from the fixed ones, therefore they are not restored